@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/martinrizk/investify/internal/handlers"
@@ -32,17 +36,41 @@ func main() {
 	
 	// API routes for stock data
 	r.HandleFunc("/api/health", handlers.APIHealthHandler).Methods("GET")
-	
+	r.HandleFunc("/api/providers/health", handlers.ProvidersHealthHandler).Methods("GET")
+	r.HandleFunc("/api/market", handlers.RequireScope("stocks:read", handlers.MarketOverviewHandler)).Methods("GET")
+	r.HandleFunc("/api/metrics", handlers.MetricsHandler).Methods("GET")
+	r.HandleFunc("/api/auth/token", handlers.IssueTokenHandler).Methods("POST")
+	r.HandleFunc("/slack/stock", handlers.SlackTickerHandler).Methods("POST")
+	r.HandleFunc("/alerts", handlers.RequireScope("stocks:read", handlers.AlertsHandler)).Methods("GET")
+	r.HandleFunc("/api/alerts", handlers.RequireScope("orders:write", handlers.RegisterAlertAPIHandler)).Methods("POST")
+
 	// Initialize WebSocket handler and set up route for real-time stock updates
 	handlers.InitWebSocketHandler()
-	r.HandleFunc("/ws/stocks/{ticker}", handlers.HandleWebSocket)
-	
+	r.HandleFunc("/ws/stocks", handlers.RequireScope("stocks:stream", handlers.HandleWebSocket))
+	r.HandleFunc("/ws/quotes", handlers.RequireScope("stocks:stream", handlers.HandleQuotesStream))
+	r.HandleFunc("/api/recommendations/stream/{ticker}", handlers.RequireScope("stocks:stream", handlers.RecommendationStreamHandler)).Methods("GET")
+
 	// API routes for polling fallback
-	r.HandleFunc("/api/stocks/{ticker}", handlers.StockAPIHandler).Methods("GET")
-	
+	r.HandleFunc("/api/stocks", handlers.RequireScope("stocks:read", handlers.StocksQueryHandler)).Methods("GET")
+	r.HandleFunc("/api/stocks/{ticker}", handlers.RequireScope("stocks:read", handlers.StockAPIHandler)).Methods("GET")
+	r.HandleFunc("/api/stocks/batch", handlers.RequireScope("stocks:read", handlers.BatchStockAPIHandler)).Methods("POST")
+	r.HandleFunc("/api/backtest/{ticker}", handlers.RequireScope("stocks:read", handlers.BacktestHandler)).Methods("GET")
+
+	// Paper-trading order execution, if Alpaca credentials are configured
+	orderExecutor, err := handlers.InitTradingHandler()
+	if err != nil {
+		log.Printf("Trading disabled: %v", err)
+	} else {
+		r.HandleFunc("/api/orders", handlers.RequireScope("orders:write", handlers.OrdersAPIHandler)).Methods("POST")
+		r.HandleFunc("/api/orders/{id}", handlers.RequireScope("orders:write", handlers.CancelOrderAPIHandler)).Methods("DELETE")
+	}
+
 	// Start the WebSocket broadcaster
 	handlers.StartPriceUpdateBroadcaster()
-	
+
+	// Relay PriceAlertMonitor's trailing-stop events onto the alerts channel
+	handlers.InitAlertsHandler()
+
 	// Serve React app static files from frontend/build
 	frontendBuildPath := filepath.Join("..", "frontend", "build")
 	staticFileServer := http.FileServer(http.Dir(frontendBuildPath))
@@ -71,6 +99,39 @@ func main() {
 		port = "8084"  // Changed to port 8084 to avoid conflict
 	}
 
+	server := &http.Server{Addr: ":" + port, Handler: r}
+
+	// Kill-switch: cancel every open order rather than leaving it working
+	// against the exchange once this process stops serving requests.
+	if orderExecutor != nil {
+		server.RegisterOnShutdown(func() {
+			if err := orderExecutor.CancelAll(context.Background()); err != nil {
+				log.Printf("Error canceling open orders on shutdown: %v", err)
+			}
+		})
+	}
+
+	// Stop every /ws/quotes per-symbol poller rather than leaving them
+	// fetching against a router that's no longer serving connections.
+	server.RegisterOnShutdown(handlers.ShutdownQuotesStream)
+
+	// On SIGTERM/SIGINT, drain in-flight requests and run the shutdown
+	// hooks above before exiting, instead of dropping connections cold.
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+		<-sigCh
+
+		log.Println("Shutting down gracefully...")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("Error during graceful shutdown: %v", err)
+		}
+	}()
+
 	log.Printf("Starting server on port %s (Python bridge: %v)", port, pythonAvailable)
-	log.Fatal(http.ListenAndServe(":"+port, r))
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
 }
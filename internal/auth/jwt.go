@@ -0,0 +1,105 @@
+// Package auth issues and validates HS256 JWTs used to authenticate API
+// clients, and enforces per-token scopes and rate limits.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claims are the JWT claims Investify issues: who the token is for, which
+// scopes it grants, and how many requests per minute it's allowed.
+type Claims struct {
+	Sub       string   `json:"sub"`
+	Scopes    []string `json:"scopes"`
+	RateLimit int      `json:"rate_limit"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// HasScope reports whether the claims grant the given scope. The "admin"
+// scope implicitly grants every other scope.
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope || s == "admin" {
+			return true
+		}
+	}
+	return false
+}
+
+const jwtHeader = `{"alg":"HS256","typ":"JWT"}`
+
+// IssueToken signs a new HS256 JWT for sub carrying scopes and a
+// requests-per-minute rate limit, valid for ttl.
+func IssueToken(secret []byte, sub string, scopes []string, rateLimit int, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Sub:       sub,
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal claims: %v", err)
+	}
+
+	signingInput := base64URLEncode([]byte(jwtHeader)) + "." + base64URLEncode(payload)
+	signature := sign(secret, signingInput)
+
+	return signingInput + "." + signature, nil
+}
+
+// ParseToken validates a JWT's HS256 signature and expiry and returns its
+// claims.
+func ParseToken(secret []byte, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	expectedSig := sign(secret, signingInput)
+	if subtle.ConstantTimeCompare([]byte(expectedSig), []byte(parts[2])) != 1 {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	payload, err := base64URLDecode(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token payload: %v", err)
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse claims: %v", err)
+	}
+
+	if claims.ExpiresAt != 0 && time.Now().Unix() > claims.ExpiresAt {
+		return nil, fmt.Errorf("token expired")
+	}
+
+	return &claims, nil
+}
+
+func sign(secret []byte, signingInput string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return base64URLEncode(mac.Sum(nil))
+}
+
+func base64URLEncode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func base64URLDecode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter enforces a per-token-bucket requests-per-minute limit, keyed
+// by the claims' sub.
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens     float64
+	capacity   float64
+	refillRate float64 // tokens per second
+	lastRefill time.Time
+}
+
+// NewRateLimiter creates an empty rate limiter; buckets are created lazily
+// per sub on first use, sized from that token's rate_limit claim.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{buckets: make(map[string]*bucket)}
+}
+
+// Allow reports whether sub may make another request under its
+// requests-per-minute limit, consuming one token if so.
+func (rl *RateLimiter) Allow(sub string, requestsPerMinute int) bool {
+	if requestsPerMinute <= 0 {
+		return true
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, ok := rl.buckets[sub]
+	if !ok {
+		b = &bucket{
+			tokens:     float64(requestsPerMinute),
+			capacity:   float64(requestsPerMinute),
+			refillRate: float64(requestsPerMinute) / 60.0,
+			lastRefill: time.Now(),
+		}
+		rl.buckets[sub] = b
+	}
+
+	elapsed := time.Since(b.lastRefill).Seconds()
+	b.tokens = minFloat(b.capacity, b.tokens+elapsed*b.refillRate)
+	b.lastRefill = time.Now()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Middleware parses the Authorization: Bearer <token> header, rejects
+// requests missing the required scope with 403, and enforces the token's
+// rate limit using limiter. secret is the HS256 signing key
+// (INVESTIFY_JWT_SECRET).
+func Middleware(secret []byte, requiredScope string, limiter *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				http.Error(w, "missing bearer token", http.StatusUnauthorized)
+				return
+			}
+			token := strings.TrimPrefix(authHeader, "Bearer ")
+
+			claims, err := ParseToken(secret, token)
+			if err != nil {
+				http.Error(w, "invalid token: "+err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			if requiredScope != "" && !claims.HasScope(requiredScope) {
+				http.Error(w, "token missing required scope: "+requiredScope, http.StatusForbidden)
+				return
+			}
+
+			if !limiter.Allow(claims.Sub, claims.RateLimit) {
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
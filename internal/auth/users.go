@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"os"
+	"time"
+)
+
+// tokenTTL is how long an issued token remains valid.
+const tokenTTL = 24 * time.Hour
+
+// configuredUser describes a user allowed to request tokens and the scopes
+// and rate limit those tokens carry. Passwords are configured out-of-band
+// via environment variables so no secrets live in source.
+type configuredUser struct {
+	password  string
+	scopes    []string
+	rateLimit int
+}
+
+// ConfiguredUsers is the set of users who may request tokens from
+// POST /api/auth/token. In a real deployment this would be backed by a user
+// store; for now it's populated from environment variables by cmd/main.go
+// via RegisterUser.
+var ConfiguredUsers = make(map[string]configuredUser)
+
+func init() {
+	// Seed default users from the environment so a deployment can issue
+	// tokens without editing code. Passwords left unset simply disable that
+	// user.
+	if password := os.Getenv("INVESTIFY_DEMO_PASSWORD"); password != "" {
+		RegisterUser("demo", password, []string{"stocks:read", "stocks:stream"}, 60)
+	}
+	if password := os.Getenv("INVESTIFY_ADMIN_PASSWORD"); password != "" {
+		RegisterUser("admin", password, []string{"admin"}, 600)
+	}
+}
+
+// RegisterUser adds or replaces a user allowed to request tokens.
+func RegisterUser(username, password string, scopes []string, rateLimit int) {
+	ConfiguredUsers[username] = configuredUser{
+		password:  password,
+		scopes:    scopes,
+		rateLimit: rateLimit,
+	}
+}
+
+// IssueTokenForUser validates username/password against ConfiguredUsers and,
+// if they match, issues a signed token carrying that user's scopes and rate
+// limit.
+func IssueTokenForUser(secret []byte, username, password string) (string, error) {
+	user, ok := ConfiguredUsers[username]
+	if !ok || user.password == "" || user.password != password {
+		return "", errInvalidCredentials
+	}
+
+	return IssueToken(secret, username, user.scopes, user.rateLimit, tokenTTL)
+}
+
+var errInvalidCredentials = invalidCredentialsError{}
+
+type invalidCredentialsError struct{}
+
+func (invalidCredentialsError) Error() string { return "invalid username or password" }
@@ -0,0 +1,173 @@
+// Package backtest replays historical bars through a prediction function one
+// bar at a time and scores the results for directional accuracy, price
+// error, and simulated PnL, so the best-performing entry threshold for a
+// ticker can be derived from its own history instead of a flat rule.
+package backtest
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/martinrizk/investify/internal/services"
+)
+
+// Config controls how Run simulates trading on top of the raw predictions.
+type Config struct {
+	// FeeRate is charged per position entered, as a fraction of the bar's
+	// close price (e.g. 0.001 for 10 bps round-trip).
+	FeeRate float64
+}
+
+// Result summarizes one ticker's walk-forward backtest.
+type Result struct {
+	Ticker              string    `json:"ticker"`
+	Bars                int       `json:"bars"`
+	DirectionalAccuracy float64   `json:"directional_accuracy"`
+	MAE                 float64   `json:"mae"`
+	RMSE                float64   `json:"rmse"`
+	PnL                 float64   `json:"pnl"`
+	MaxDrawdown         float64   `json:"max_drawdown"`
+	EquityCurve         []float64 `json:"equity_curve"`
+
+	// BestThreshold is the bar-over-bar change-percent cutoff ("enter long
+	// when changePct > threshold") that produced the highest PnL of the
+	// candidates swept, for GetRuleBasedRecommendation's fallback to use
+	// instead of a flat stock.Change > 0 rule.
+	BestThreshold float64 `json:"best_threshold"`
+
+	// ProfitFactor is gross trade gains divided by gross trade losses over
+	// the backtest, the realized take-profit-to-stop-loss ratio
+	// TFModelService.RiskLevels smooths its takeProfitFactor toward. 0 if
+	// there were no losing bars to divide by.
+	ProfitFactor float64 `json:"profit_factor"`
+}
+
+// thresholdCandidates are the change-percent cutoffs swept to find each
+// ticker's BestThreshold.
+var thresholdCandidates = []float64{-2, -1, -0.5, 0, 0.5, 1, 2}
+
+// Run walks bars one at a time, feeding a synthetic StockInfo built from
+// each bar into predict, and scores the prediction against the next bar's
+// realized close. predict is typically services.PredictStockMovement, with
+// tests free to substitute a stub.
+func Run(ticker string, bars []services.Bar, predict func(*services.StockInfo) (*services.StockPrediction, error), cfg Config) (*Result, error) {
+	if len(bars) < 2 {
+		return nil, fmt.Errorf("backtest: need at least 2 bars, got %d", len(bars))
+	}
+
+	result := &Result{Ticker: ticker, Bars: len(bars) - 1}
+
+	var correct int
+	var sumAbsErr, sumSqErr float64
+	var equity, peak, maxDrawdown float64
+	var grossGain, grossLoss float64
+	equityCurve := make([]float64, 0, len(bars)-1)
+
+	prevClose := bars[0].Close
+	for i := 0; i < len(bars)-1; i++ {
+		bar := bars[i]
+		next := bars[i+1]
+
+		change := bar.Close - prevClose
+		changePct := "0.00%"
+		if prevClose != 0 {
+			changePct = fmt.Sprintf("%.2f%%", (change/prevClose)*100)
+		}
+
+		snapshot := &services.StockInfo{
+			Ticker:    ticker,
+			Price:     bar.Close,
+			Open:      bar.Open,
+			High:      bar.High,
+			Low:       bar.Low,
+			Change:    change,
+			ChangePct: changePct,
+		}
+
+		prediction, err := predict(snapshot)
+		if err == nil && prediction != nil {
+			realized := next.Close - bar.Close
+			if (prediction.Direction == "UP" && realized > 0) || (prediction.Direction == "DOWN" && realized < 0) {
+				correct++
+			}
+
+			priceErr := prediction.PredictedPrice - next.Close
+			sumAbsErr += math.Abs(priceErr)
+			sumSqErr += priceErr * priceErr
+
+			position := 0.0
+			switch prediction.Direction {
+			case "UP":
+				position = 1
+			case "DOWN":
+				position = -1
+			}
+			if position != 0 {
+				pnl := position*realized - cfg.FeeRate*bar.Close
+				equity += pnl
+				if pnl > 0 {
+					grossGain += pnl
+				} else {
+					grossLoss += -pnl
+				}
+			}
+		}
+
+		equityCurve = append(equityCurve, equity)
+		if equity > peak {
+			peak = equity
+		}
+		if drawdown := peak - equity; drawdown > maxDrawdown {
+			maxDrawdown = drawdown
+		}
+
+		prevClose = bar.Close
+	}
+
+	if result.Bars > 0 {
+		result.DirectionalAccuracy = float64(correct) / float64(result.Bars)
+		result.MAE = sumAbsErr / float64(result.Bars)
+		result.RMSE = math.Sqrt(sumSqErr / float64(result.Bars))
+	}
+	result.PnL = equity
+	result.MaxDrawdown = maxDrawdown
+	result.EquityCurve = equityCurve
+	result.BestThreshold = bestThreshold(bars, cfg)
+	if grossLoss > 0 {
+		result.ProfitFactor = grossGain / grossLoss
+	}
+
+	return result, nil
+}
+
+// bestThreshold sweeps thresholdCandidates against the realized bar-over-bar
+// changes, picking the cutoff whose resulting "enter long when changePct >
+// threshold" rule would have produced the highest cumulative PnL.
+func bestThreshold(bars []services.Bar, cfg Config) float64 {
+	best := thresholdCandidates[0]
+	bestPnL := math.Inf(-1)
+
+	for _, threshold := range thresholdCandidates {
+		pnl := 0.0
+		prevClose := bars[0].Close
+		for i := 0; i < len(bars)-1; i++ {
+			bar := bars[i]
+			next := bars[i+1]
+			if prevClose == 0 {
+				prevClose = bar.Close
+				continue
+			}
+
+			changePct := (bar.Close - prevClose) / prevClose * 100
+			if changePct > threshold {
+				pnl += next.Close - bar.Close - cfg.FeeRate*bar.Close
+			}
+			prevClose = bar.Close
+		}
+		if pnl > bestPnL {
+			bestPnL = pnl
+			best = threshold
+		}
+	}
+	return best
+}
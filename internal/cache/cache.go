@@ -0,0 +1,174 @@
+// Package cache provides a thread-safe, size-bounded, TTL-evicting cache
+// with singleflight-style request coalescing, used to avoid hammering
+// upstream quote providers when many handlers ask for the same ticker at
+// once.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats are cumulative counters for a QuoteCache, suitable for exposing on a
+// /metrics endpoint.
+type Stats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+}
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// QuoteCache is a fixed-capacity, TTL-evicting LRU cache guarded by a
+// sync.RWMutex, with GetOrFetch coalescing concurrent lookups for the same
+// key into a single call of the supplied fetch function.
+type QuoteCache struct {
+	mu       sync.RWMutex
+	ttl      time.Duration
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+
+	hits      int64
+	misses    int64
+	evictions int64
+
+	inflightMu sync.Mutex
+	inflight   map[string]*call
+}
+
+type call struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+}
+
+// New creates a QuoteCache holding at most capacity entries, each valid for
+// ttl after it is set.
+func New(capacity int, ttl time.Duration) *QuoteCache {
+	return &QuoteCache{
+		ttl:      ttl,
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		inflight: make(map[string]*call),
+	}
+}
+
+// Get returns the cached value for key if present and not expired.
+func (c *QuoteCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+
+	e := elem.Value.(*entry)
+	if time.Now().After(e.expiresAt) {
+		c.removeElement(elem)
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	atomic.AddInt64(&c.hits, 1)
+	return e.value, true
+}
+
+// Set stores value for key, evicting the least-recently-used entry if the
+// cache is at capacity.
+func (c *QuoteCache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*entry).value = value
+		elem.Value.(*entry).expiresAt = time.Now().Add(c.ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&entry{
+		key:       key,
+		value:     value,
+		expiresAt: time.Now().Add(c.ttl),
+	})
+	c.items[key] = elem
+
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.removeElement(oldest)
+			atomic.AddInt64(&c.evictions, 1)
+		}
+	}
+}
+
+// removeElement deletes elem from both the map and the LRU list. Callers
+// must hold c.mu.
+func (c *QuoteCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*entry).key)
+}
+
+// GetOrFetch returns the cached value for key, or calls fetch if it's
+// missing or expired. Concurrent calls for the same key while a fetch is in
+// flight all wait on and share that single call's result instead of each
+// triggering their own upstream request.
+func (c *QuoteCache) GetOrFetch(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	c.inflightMu.Lock()
+	if existing, ok := c.inflight[key]; ok {
+		c.inflightMu.Unlock()
+		existing.wg.Wait()
+		return existing.value, existing.err
+	}
+
+	call := &call{}
+	call.wg.Add(1)
+	c.inflight[key] = call
+	c.inflightMu.Unlock()
+
+	value, err := fetch()
+	call.value, call.err = value, err
+	call.wg.Done()
+
+	c.inflightMu.Lock()
+	delete(c.inflight, key)
+	c.inflightMu.Unlock()
+
+	if err == nil {
+		c.Set(key, value)
+	}
+	return value, err
+}
+
+// Stats returns a snapshot of the cache's cumulative hit/miss/eviction
+// counters.
+func (c *QuoteCache) Stats() Stats {
+	return Stats{
+		Hits:      atomic.LoadInt64(&c.hits),
+		Misses:    atomic.LoadInt64(&c.misses),
+		Evictions: atomic.LoadInt64(&c.evictions),
+	}
+}
+
+// Len returns the number of entries currently cached, including any not yet
+// lazily evicted for having expired.
+func (c *QuoteCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.order.Len()
+}
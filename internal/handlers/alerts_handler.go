@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/martinrizk/investify/internal/services"
+)
+
+// alertMonitor backs AlertsHandler and runs for the lifetime of the
+// process; unlike trading, it has no credentials to configure, so it's
+// always live.
+var alertMonitor = services.NewPriceAlertMonitor()
+
+// InitAlertsHandler starts relaying alertMonitor's events to the stocks
+// WebSocket channel, so a fired stop shows up in the UI the same way a
+// price tick does.
+func InitAlertsHandler() {
+	go relayAlertEvents(alertMonitor)
+}
+
+// relayAlertEvents forwards every event off alertMonitor.Events() to the
+// stocks channel's subscribers for that event's ticker.
+func relayAlertEvents(monitor *services.PriceAlertMonitor) {
+	for event := range monitor.Events() {
+		hub.broadcast(channelAlerts, event.Ticker, alertMessage{
+			Type:   "alert",
+			Ticker: event.Ticker,
+			Side:   event.Side,
+			Price:  event.Price,
+			Reason: event.Reason,
+		})
+	}
+}
+
+// RegisterAlertAPIRequest is the body of a POST /api/alerts request.
+type RegisterAlertAPIRequest struct {
+	Ticker         string  `json:"ticker"`
+	EntryPrice     float64 `json:"entry_price"`
+	TakeProfitPct  float64 `json:"take_profit_pct"`
+	InitialStopPct float64 `json:"initial_stop_pct"`
+	TrailingPct    float64 `json:"trailing_pct"`
+}
+
+// RegisterAlertAPIHandler handles POST /api/alerts, arming a trailing-stop
+// monitor for a ticker.
+func RegisterAlertAPIHandler(w http.ResponseWriter, r *http.Request) {
+	var req RegisterAlertAPIRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Ticker == "" || req.EntryPrice <= 0 {
+		http.Error(w, "ticker and a positive entry_price are required", http.StatusBadRequest)
+		return
+	}
+
+	alertMonitor.RegisterAlert(req.Ticker, req.EntryPrice, req.TakeProfitPct, req.InitialStopPct, req.TrailingPct)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AlertsHandler handles GET /alerts, reporting the armed/unarmed status of
+// every registered ticker.
+func AlertsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(alertMonitor.Snapshot()); err != nil {
+		log.Printf("Error encoding alerts response: %v", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
@@ -4,10 +4,20 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/martinrizk/investify/internal/services"
 )
 
+// maxBatchTickers caps how many tickers a single batch request may include.
+const maxBatchTickers = 100
+
+// BatchQuoteRequest is the body of a POST /api/stocks/batch request.
+type BatchQuoteRequest struct {
+	Tickers []string `json:"tickers"`
+}
+
 // StockAPIHandler handles requests for stock data via API endpoint
 func StockAPIHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -49,3 +59,79 @@ func StockAPIHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 	}
 }
+
+// BatchStockAPIHandler handles POST /api/stocks/batch, returning a map of
+// ticker to StockUpdate for a watchlist in a single response.
+func BatchStockAPIHandler(w http.ResponseWriter, r *http.Request) {
+	var req BatchQuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Tickers) == 0 {
+		http.Error(w, "At least one ticker required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Tickers) > maxBatchTickers {
+		http.Error(w, "Too many tickers, limit is 100", http.StatusBadRequest)
+		return
+	}
+
+	quotes, err := services.FetchStockInfos(req.Tickers)
+	if err != nil {
+		http.Error(w, "Error fetching stock data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make(map[string]StockUpdate, len(quotes))
+	for ticker, info := range quotes {
+		technical, err := fetchTechnicalIndicators(ticker)
+		if err != nil {
+			technical = nil
+		}
+		response[ticker] = StockUpdate{
+			Ticker:      ticker,
+			Price:       info.Price,
+			Change:      info.Change,
+			ChangePct:   info.ChangePct,
+			LastUpdated: formatTime(nil),
+			Technical:   technical,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding batch stock response: %v", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
+
+// StocksQueryHandler handles GET /api/stocks?symbols=AAPL,MSFT,TSLA,
+// returning full StockInfo (including predictions and recommendations) for
+// each symbol in the order given, via a single batched upstream request.
+func StocksQueryHandler(w http.ResponseWriter, r *http.Request) {
+	symbols := r.URL.Query().Get("symbols")
+	if symbols == "" {
+		http.Error(w, "symbols query parameter required", http.StatusBadRequest)
+		return
+	}
+
+	tickers := strings.Split(symbols, ",")
+	if len(tickers) > maxBatchTickers {
+		http.Error(w, "Too many tickers, limit is 100", http.StatusBadRequest)
+		return
+	}
+
+	stocks, err := services.FetchStocksBatch(tickers)
+	if err != nil {
+		http.Error(w, "Error fetching stock data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stocks); err != nil {
+		log.Printf("Error encoding stocks query response: %v", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
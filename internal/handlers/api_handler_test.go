@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/gorilla/mux"
@@ -11,6 +12,10 @@ import (
 
 // TestStockAPIHandler tests the API endpoint for fetching stock data
 func TestStockAPIHandler(t *testing.T) {
+	// Force simulated data so the test doesn't depend on live providers.
+	os.Setenv("INVESTIFY_PROVIDER", "sim")
+	defer os.Unsetenv("INVESTIFY_PROVIDER")
+
 	// Initialize the handler
 	InitWebSocketHandler()
 
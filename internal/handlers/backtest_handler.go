@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/martinrizk/investify/internal/backtest"
+	"github.com/martinrizk/investify/internal/services"
+)
+
+// defaultBacktestFeeRate is charged per position when the request doesn't
+// specify a `fee_rate` query param.
+const defaultBacktestFeeRate = 0.001
+
+// BacktestHandler handles GET /api/backtest/{ticker}, replaying a year of
+// historical bars through the ML predictor and reporting directional
+// accuracy, price error, and simulated PnL, then persisting the
+// best-performing entry threshold for GetRuleBasedRecommendation's fallback
+// to use and feeding the realized profit factor back into the TF model's
+// take-profit smoothing.
+func BacktestHandler(w http.ResponseWriter, r *http.Request) {
+	ticker := mux.Vars(r)["ticker"]
+	if ticker == "" {
+		http.Error(w, "Ticker symbol required", http.StatusBadRequest)
+		return
+	}
+
+	feeRate := defaultBacktestFeeRate
+	if raw := r.URL.Query().Get("fee_rate"); raw != "" {
+		if parsed, err := strconv.ParseFloat(raw, 64); err == nil {
+			feeRate = parsed
+		}
+	}
+
+	bars, err := services.FetchHistorical(ticker, "1Day", "1y")
+	if err != nil {
+		http.Error(w, "Error fetching historical data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result, err := backtest.Run(ticker, bars, services.PredictStockMovement, backtest.Config{FeeRate: feeRate})
+	if err != nil {
+		http.Error(w, "Error running backtest: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	services.SetBacktestThreshold(ticker, result.BestThreshold)
+	if result.ProfitFactor > 0 {
+		services.RecordRealizedProfitFactor(ticker, result.ProfitFactor)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/martinrizk/investify/internal/services"
 )
 
 // ReactAppHandler serves the React frontend application
@@ -32,3 +34,44 @@ func APIHealthHandler(w http.ResponseWriter, r *http.Request) {
 	
 	json.NewEncoder(w).Encode(response)
 }
+
+// ProvidersHealthHandler returns circuit breaker state, last error, next
+// retry time, and success rate for every registered stock data provider so
+// operators can see why demo data is being served.
+func ProvidersHealthHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	health := services.ProvidersHealth()
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"providers": health,
+	})
+}
+
+// MarketOverviewHandler returns a snapshot of major US and international
+// indices, commodities, currencies, and the 10-year Treasury yield, for a
+// dashboard view beyond a single ticker lookup.
+func MarketOverviewHandler(w http.ResponseWriter, r *http.Request) {
+	overview, err := services.GetMarketOverview()
+	if err != nil {
+		http.Error(w, "Error fetching market overview: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(overview); err != nil {
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
+
+// MetricsHandler exposes the quote cache's cumulative hit/miss/eviction
+// counters and the WebSocket broadcast pipeline's tick/fan-out counters so
+// operators can judge whether the cache is sized well and whether upstream
+// call volume is actually being reduced.
+func MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"cache":     services.CacheStats(),
+		"websocket": services.WSMetricsSnapshot(),
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/martinrizk/investify/internal/services"
+)
+
+// RecommendationStreamHandler serves GET /api/recommendations/stream/{ticker}
+// as a text/event-stream of services.Recommendation events, computed only
+// when the ticker's price has moved enough to matter - see
+// services.SubscribeToRecommendations - rather than re-polling the whole
+// page on a timer.
+func RecommendationStreamHandler(w http.ResponseWriter, r *http.Request) {
+	ticker := mux.Vars(r)["ticker"]
+	if ticker == "" {
+		http.Error(w, "Ticker symbol required", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	recs, unsubscribe := services.SubscribeToRecommendations(ticker)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case rec, open := <-recs:
+			if !open {
+				return
+			}
+			payload, err := json.Marshal(rec)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", payload)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/martinrizk/investify/internal/services"
+)
+
+// slackTimestampSkew is how far X-Slack-Request-Timestamp may drift from
+// now before a request is rejected as a replay, per Slack's own signature
+// verification guidance.
+const slackTimestampSkew = 5 * time.Minute
+
+// slackSigningSecret returns the shared secret configured via
+// SLACK_SIGNING_SECRET, used to verify X-Slack-Signature.
+func slackSigningSecret() []byte {
+	return []byte(os.Getenv("SLACK_SIGNING_SECRET"))
+}
+
+// slackAttachment is the subset of Slack's message attachment schema this
+// handler populates: https://api.slack.com/reference/messages/attachment.
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Title  string       `json:"title"`
+	Text   string       `json:"text"`
+	Fields []slackField `json:"fields"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// slackResponse is a Slack slash-command response payload.
+type slackResponse struct {
+	ResponseType string            `json:"response_type"`
+	Attachments  []slackAttachment `json:"attachments"`
+}
+
+// SlackTickerHandler responds to a Slack slash command (e.g. `/stock AAPL`)
+// with a quote, ML prediction, and rule-based recommendation for the
+// requested ticker, rendered as a Slack message attachment.
+func SlackTickerHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	r.Body.Close()
+
+	if err := verifySlackSignature(r.Header, body); err != nil {
+		http.Error(w, "Invalid request signature: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// verifySlackSignature above already drained r.Body to compute the
+	// HMAC; ParseForm needs to read it again to populate r.PostForm.
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Error parsing request body", http.StatusBadRequest)
+		return
+	}
+	ticker := strings.TrimSpace(r.PostForm.Get("text"))
+	if ticker == "" {
+		http.Error(w, "Usage: /stock TICKER", http.StatusBadRequest)
+		return
+	}
+
+	stock, err := services.SearchStockSecure(ticker)
+	if err != nil {
+		http.Error(w, "Error fetching stock data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(slackResponse{
+		ResponseType: "in_channel",
+		Attachments:  []slackAttachment{stockSlackAttachment(stock)},
+	})
+}
+
+// stockSlackAttachment builds the Slack attachment for stock: green/red/
+// gray for up/down/flat, the company name linking to its ticker page, and
+// the TF prediction plus rule-based recommendation as fields.
+func stockSlackAttachment(stock *services.StockInfo) slackAttachment {
+	color := "warning"
+	if stock.Change > 0 {
+		color = "good"
+	} else if stock.Change < 0 {
+		color = "danger"
+	}
+
+	attachment := slackAttachment{
+		Color: color,
+		Title: fmt.Sprintf("<https://finance.yahoo.com/quote/%s|%s (%s)>", stock.Ticker, stock.CompanyName, stock.Ticker),
+		Text:  fmt.Sprintf("$%.2f (%s)", stock.Price, stock.ChangePct),
+	}
+
+	if prediction, err := services.PredictStockMovement(stock); err == nil && prediction != nil {
+		attachment.Fields = append(attachment.Fields, slackField{
+			Title: "ML Prediction",
+			Value: fmt.Sprintf("%s (%.1f%% confidence)", prediction.Direction, prediction.Confidence*100),
+			Short: true,
+		})
+	}
+
+	if rec := services.GetRuleBasedRecommendation(stock); rec != nil {
+		attachment.Fields = append(attachment.Fields, slackField{
+			Title: "Recommendation",
+			Value: rec.Text,
+			Short: true,
+		})
+	}
+
+	return attachment
+}
+
+// verifySlackSignature checks X-Slack-Signature against an HMAC-SHA256 of
+// the Slack-documented base string ("v0:timestamp:body") signed with the
+// configured signing secret, and rejects requests whose
+// X-Slack-Request-Timestamp is outside slackTimestampSkew of now to guard
+// against replay. See https://api.slack.com/authentication/verifying-requests-from-slack.
+func verifySlackSignature(header http.Header, body []byte) error {
+	secret := slackSigningSecret()
+	if len(secret) == 0 {
+		return fmt.Errorf("SLACK_SIGNING_SECRET not configured")
+	}
+
+	timestampHeader := header.Get("X-Slack-Request-Timestamp")
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid X-Slack-Request-Timestamp")
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age > slackTimestampSkew || age < -slackTimestampSkew {
+		return fmt.Errorf("request timestamp outside the %s replay window", slackTimestampSkew)
+	}
+
+	signature := header.Get("X-Slack-Signature")
+	if signature == "" {
+		return fmt.Errorf("missing X-Slack-Signature")
+	}
+
+	baseString := fmt.Sprintf("v0:%s:%s", timestampHeader, body)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(baseString))
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) != 1 {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signSlackRequest computes a valid X-Slack-Signature for body and
+// timestamp under secret, mirroring Slack's own signing scheme.
+func signSlackRequest(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("v0:%s:%s", timestamp, body)))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newSignedSlackRequest(t *testing.T, secret, text string, timestamp time.Time) *http.Request {
+	t.Helper()
+	form := url.Values{"text": {text}}
+	body := form.Encode()
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+	req := httptest.NewRequest("POST", "/slack/stock", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", signSlackRequest(secret, ts, []byte(body)))
+	return req
+}
+
+// TestSlackTickerHandlerValidSignature verifies a correctly-signed, fresh
+// request is accepted and returns an attachment for the requested ticker.
+func TestSlackTickerHandlerValidSignature(t *testing.T) {
+	os.Setenv("INVESTIFY_PROVIDER", "sim")
+	os.Setenv("SLACK_SIGNING_SECRET", "test-secret")
+	defer os.Unsetenv("INVESTIFY_PROVIDER")
+	defer os.Unsetenv("SLACK_SIGNING_SECRET")
+
+	InitWebSocketHandler()
+
+	req := newSignedSlackRequest(t, "test-secret", "AAPL", time.Now())
+	rr := httptest.NewRecorder()
+	SlackTickerHandler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "AAPL") {
+		t.Errorf("expected response to mention AAPL, got %s", rr.Body.String())
+	}
+}
+
+// TestSlackTickerHandlerRejectsBadSignature verifies a request signed with
+// the wrong secret is rejected.
+func TestSlackTickerHandlerRejectsBadSignature(t *testing.T) {
+	os.Setenv("INVESTIFY_PROVIDER", "sim")
+	os.Setenv("SLACK_SIGNING_SECRET", "test-secret")
+	defer os.Unsetenv("INVESTIFY_PROVIDER")
+	defer os.Unsetenv("SLACK_SIGNING_SECRET")
+
+	req := newSignedSlackRequest(t, "wrong-secret", "AAPL", time.Now())
+	rr := httptest.NewRecorder()
+	SlackTickerHandler(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a bad signature, got %d", rr.Code)
+	}
+}
+
+// TestSlackTickerHandlerRejectsReplay verifies a request signed outside the
+// replay window is rejected even with a correct signature.
+func TestSlackTickerHandlerRejectsReplay(t *testing.T) {
+	os.Setenv("INVESTIFY_PROVIDER", "sim")
+	os.Setenv("SLACK_SIGNING_SECRET", "test-secret")
+	defer os.Unsetenv("INVESTIFY_PROVIDER")
+	defer os.Unsetenv("SLACK_SIGNING_SECRET")
+
+	req := newSignedSlackRequest(t, "test-secret", "AAPL", time.Now().Add(-10*time.Minute))
+	rr := httptest.NewRecorder()
+	SlackTickerHandler(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a replayed timestamp, got %d", rr.Code)
+	}
+}
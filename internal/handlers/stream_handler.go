@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/martinrizk/investify/internal/services/stream"
+)
+
+// quotesHub is the single Hub backing HandleQuotesStream, fanning live
+// quotes out to every connected /ws/quotes client.
+var quotesHub = stream.NewHub(quotesPollInterval())
+
+// quotesPollInterval reads STREAM_POLL_INTERVAL_SECONDS, falling back to
+// stream.DefaultPollInterval if it's unset or invalid.
+func quotesPollInterval() time.Duration {
+	raw := os.Getenv("STREAM_POLL_INTERVAL_SECONDS")
+	if raw == "" {
+		return stream.DefaultPollInterval
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds <= 0 {
+		return stream.DefaultPollInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// quotesStreamPongWait/quotesStreamPingPeriod bound the ping/pong keepalive
+// for /ws/quotes: the connection is considered dead if no pong (or other
+// message) arrives within quotesStreamPongWait, and pingPeriod is kept
+// comfortably under that so a ping always arrives in time.
+const (
+	quotesStreamPongWait   = 60 * time.Second
+	quotesStreamPingPeriod = (quotesStreamPongWait * 9) / 10
+)
+
+// quotesControlFrame is a client->server message on /ws/quotes, e.g.
+// {"action":"subscribe","symbols":["AAPL","TSLA"]}.
+type quotesControlFrame struct {
+	Action  string   `json:"action"`
+	Symbols []string `json:"symbols"`
+}
+
+// streamClient is one connected /ws/quotes WebSocket. It implements
+// stream.Client with a buffered send queue so one slow reader can't stall
+// delivery to everyone else; a full queue drops the tick.
+type streamClient struct {
+	conn *websocket.Conn
+	send chan stream.Tick
+
+	mu      sync.Mutex
+	symbols map[string]bool
+}
+
+func newStreamClient(conn *websocket.Conn) *streamClient {
+	return &streamClient{
+		conn:    conn,
+		send:    make(chan stream.Tick, stream.ClientSendBuffer),
+		symbols: make(map[string]bool),
+	}
+}
+
+// Send implements stream.Client, dropping the tick if c isn't keeping up.
+func (c *streamClient) Send(t stream.Tick) {
+	select {
+	case c.send <- t:
+	default:
+		log.Printf("stream: dropping tick for slow client (buffer full)")
+	}
+}
+
+// ShutdownQuotesStream stops every running per-symbol poller on quotesHub,
+// for use as a graceful-shutdown hook.
+func ShutdownQuotesStream() {
+	quotesHub.Shutdown()
+}
+
+// HandleQuotesStream upgrades to a WebSocket and streams live StockInfo
+// plus ML predictions for whatever symbols the client subscribes to via
+// {"action":"subscribe"/"unsubscribe","symbols":[...]} control frames,
+// backed by the shared per-symbol quotesHub.
+func HandleQuotesStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("Error upgrading to websocket for quote stream: %v", err)
+		return
+	}
+
+	c := newStreamClient(conn)
+	log.Printf("Quote stream client connected")
+
+	go quotesWritePump(c)
+	quotesReadPump(c)
+}
+
+// quotesReadPump reads control frames until the connection drops, applying
+// subscribe/unsubscribe requests against quotesHub. It runs on the
+// goroutine that called HandleQuotesStream, and cleans up c's
+// subscriptions and send queue on return.
+func quotesReadPump(c *streamClient) {
+	defer func() {
+		quotesHub.RemoveClient(c)
+		close(c.send)
+		c.conn.Close()
+		log.Printf("Quote stream client disconnected")
+	}()
+
+	c.conn.SetReadDeadline(time.Now().Add(quotesStreamPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(quotesStreamPongWait))
+		return nil
+	})
+
+	for {
+		var frame quotesControlFrame
+		if err := c.conn.ReadJSON(&frame); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				log.Printf("Quote stream error: %v", err)
+			}
+			return
+		}
+
+		symbols := make([]string, 0, len(frame.Symbols))
+		for _, s := range frame.Symbols {
+			if s = strings.ToUpper(strings.TrimSpace(s)); s != "" {
+				symbols = append(symbols, s)
+			}
+		}
+
+		switch frame.Action {
+		case "subscribe":
+			c.mu.Lock()
+			for _, s := range symbols {
+				c.symbols[s] = true
+			}
+			c.mu.Unlock()
+			for _, s := range symbols {
+				quotesHub.Subscribe(c, s)
+			}
+		case "unsubscribe":
+			c.mu.Lock()
+			for _, s := range symbols {
+				delete(c.symbols, s)
+			}
+			c.mu.Unlock()
+			for _, s := range symbols {
+				quotesHub.Unsubscribe(c, s)
+			}
+		default:
+			log.Printf("Quote stream: ignoring unknown action %q", frame.Action)
+		}
+	}
+}
+
+// quotesWritePump serializes every write to c's connection through its send
+// queue and keeps the connection alive with periodic pings.
+func quotesWritePump(c *streamClient) {
+	ticker := time.NewTicker(quotesStreamPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case tick, ok := <-c.send:
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteJSON(tick); err != nil {
+				log.Printf("Error sending quote stream tick: %v", err)
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+				return
+			}
+		}
+	}
+}
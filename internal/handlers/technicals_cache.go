@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/martinrizk/investify/internal/cache"
+	"github.com/martinrizk/investify/internal/services"
+)
+
+// technicalsCacheCapacity bounds how many tickers' computed indicators are
+// held at once, evicting the least-recently-used entry once full.
+const technicalsCacheCapacity = 200
+
+// technicalsCacheTTL is a backstop expiry in case a ticker's bar window
+// stops advancing (e.g. a delisted symbol); ordinarily entries are
+// invalidated by barTime changing, not by age.
+const technicalsCacheTTL = 30 * time.Minute
+
+// technicalsCache holds the last-computed technical indicators per ticker,
+// keyed by the timestamp of the bar they were computed from, so
+// broadcastTechnicals only pays for recomputation when the underlying bar
+// window actually advances rather than on every tick.
+var technicalsCache = cache.New(technicalsCacheCapacity, technicalsCacheTTL)
+
+// technicalsCacheEntry is the value stored in technicalsCache.
+type technicalsCacheEntry struct {
+	barTime time.Time
+	data    map[string]interface{}
+}
+
+// technicalsForTicker returns ticker's technical indicators, recomputing
+// them only if the latest available bar is newer than the one the cached
+// entry was computed from.
+func technicalsForTicker(ticker string) (map[string]interface{}, error) {
+	latestBarTime := latestBarTimestamp(ticker)
+
+	if cached, ok := technicalsCache.Get(ticker); ok {
+		entry := cached.(*technicalsCacheEntry)
+		if !latestBarTime.IsZero() && !latestBarTime.After(entry.barTime) {
+			return entry.data, nil
+		}
+	}
+
+	data, err := fetchTechnicalIndicators(ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	services.IncTechnicalsRecomputed()
+	technicalsCache.Set(ticker, &technicalsCacheEntry{barTime: latestBarTime, data: data})
+	return data, nil
+}
+
+// latestBarTimestamp returns the timestamp of ticker's most recent daily
+// bar, or the zero time if it can't be determined (in which case the
+// technicals cache is bypassed rather than trusted).
+func latestBarTimestamp(ticker string) time.Time {
+	bars, err := services.FetchHistorical(ticker, "1Day", "5d")
+	if err != nil || len(bars) == 0 {
+		return time.Time{}
+	}
+	return bars[len(bars)-1].Timestamp
+}
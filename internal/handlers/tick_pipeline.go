@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/martinrizk/investify/internal/services"
+)
+
+// tickDebounceInterval bounds how often a single symbol's coalescer flushes
+// to the trades channel, regardless of how fast ticks arrive from the
+// Alpaca stream or the polling fallback - at most once per 250ms per
+// symbol, per chunk3-5.
+const tickDebounceInterval = 250 * time.Millisecond
+
+// Tick is one raw price observation for a symbol, from whatever source
+// produced it (the Alpaca stream, or the polling fallback standing in for
+// it when no real stream is configured).
+type Tick struct {
+	Ticker    string
+	Price     float64
+	Change    float64
+	ChangePct string
+	Timestamp time.Time
+}
+
+// tickCoalescer debounces a single symbol's ticks: the first tick in an
+// idle period schedules a flush tickDebounceInterval later, which
+// broadcasts whatever the latest tick was by then. Bursts faster than the
+// interval collapse into that one flush instead of one broadcast each.
+type tickCoalescer struct {
+	mu      sync.Mutex
+	latest  Tick
+	pending bool
+	timer   *time.Timer
+}
+
+// ingest records t as the latest tick for this symbol, scheduling a flush
+// if one isn't already pending.
+func (c *tickCoalescer) ingest(t Tick) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.latest = t
+	c.pending = true
+	if c.timer == nil {
+		c.timer = time.AfterFunc(tickDebounceInterval, c.flush)
+	}
+}
+
+// flush broadcasts the latest pending tick, if any, and clears the timer so
+// the next ingest schedules a fresh one.
+func (c *tickCoalescer) flush() {
+	c.mu.Lock()
+	tick, pending := c.latest, c.pending
+	c.pending = false
+	c.timer = nil
+	c.mu.Unlock()
+
+	if !pending {
+		return
+	}
+
+	hub.broadcast(channelTrades, tick.Ticker, tradeMessage{
+		Type:      "t",
+		Ticker:    tick.Ticker,
+		Price:     tick.Price,
+		Change:    tick.Change,
+		ChangePct: tick.ChangePct,
+		Timestamp: formatTime(&tick.Timestamp),
+	})
+}
+
+// tickPipeline is the process-wide set of per-symbol coalescers feeding the
+// trades channel. Every tick source (the Alpaca stream, the polling
+// fallback) publishes through ingestTick instead of broadcasting directly,
+// so a symbol with many ticks in flight still only reaches its subscribers
+// at the debounced rate.
+var tickPipeline = struct {
+	mu         sync.Mutex
+	coalescers map[string]*tickCoalescer
+}{coalescers: make(map[string]*tickCoalescer)}
+
+// ingestTick feeds t into its symbol's coalescer, creating one if this is
+// the first tick seen for that symbol.
+func ingestTick(t Tick) {
+	services.IncTicksReceived()
+
+	tickPipeline.mu.Lock()
+	c, ok := tickPipeline.coalescers[t.Ticker]
+	if !ok {
+		c = &tickCoalescer{}
+		tickPipeline.coalescers[t.Ticker] = c
+	}
+	tickPipeline.mu.Unlock()
+
+	c.ingest(t)
+}
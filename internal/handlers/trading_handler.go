@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/martinrizk/investify/internal/trading"
+)
+
+// orderExecutor is nil unless InitTradingHandler successfully configured an
+// Exchange (i.e. ALPACA_KEY_ID/ALPACA_SECRET_KEY are set), in which case
+// order submission endpoints are live; otherwise they report 503.
+var orderExecutor *trading.OrderExecutor
+
+// InitTradingHandler builds an OrderExecutor against Alpaca's paper-trading
+// API and starts relaying its order events onto the orders WebSocket
+// channel. It returns the executor (for the kill-switch registered in
+// main), or an error if Alpaca credentials aren't configured - in which
+// case trading endpoints remain disabled but the rest of the app still
+// runs.
+func InitTradingHandler() (*trading.OrderExecutor, error) {
+	exchange, err := trading.NewAlpacaExchange()
+	if err != nil {
+		return nil, fmt.Errorf("trading handler disabled: %v", err)
+	}
+
+	orderExecutor = trading.NewOrderExecutor(exchange)
+	go relayOrderEvents(orderExecutor)
+
+	return orderExecutor, nil
+}
+
+// relayOrderEvents forwards every event off executor.Events() to the orders
+// channel's subscribers for that order's symbol.
+func relayOrderEvents(executor *trading.OrderExecutor) {
+	for event := range executor.Events() {
+		order := event.Order
+		hub.broadcast(channelOrders, order.Symbol, orderMessage{
+			Type:           "order",
+			Ticker:         order.Symbol,
+			OrderID:        order.ID,
+			Side:           string(order.Side),
+			OrderType:      string(order.Type),
+			Qty:            order.Qty,
+			FilledQty:      order.FilledQty,
+			FilledAvgPrice: order.FilledAvgPrice,
+			Status:         string(order.Status),
+			Event:          event.Type,
+		})
+	}
+}
+
+// SubmitOrderAPIRequest is the body of a POST /api/orders request.
+type SubmitOrderAPIRequest struct {
+	Symbol      string  `json:"symbol"`
+	Side        string  `json:"side"`
+	Type        string  `json:"type"`
+	Qty         float64 `json:"qty"`
+	LimitPrice  float64 `json:"limit_price,omitempty"`
+	TimeInForce string  `json:"time_in_force,omitempty"`
+}
+
+// OrdersAPIHandler handles POST /api/orders, submitting an order through the
+// trading package's OrderExecutor.
+func OrdersAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if orderExecutor == nil {
+		http.Error(w, "Trading is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req SubmitOrderAPIRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Symbol == "" || req.Qty <= 0 {
+		http.Error(w, "symbol and a positive qty are required", http.StatusBadRequest)
+		return
+	}
+
+	timeInForce := trading.TimeInForceDay
+	if req.TimeInForce != "" {
+		timeInForce = trading.TimeInForce(req.TimeInForce)
+	}
+	orderType := trading.OrderTypeMarket
+	if req.Type != "" {
+		orderType = trading.OrderType(req.Type)
+	}
+
+	order, err := orderExecutor.SubmitOrder(r.Context(), trading.SubmitOrderRequest{
+		Symbol:      req.Symbol,
+		Side:        trading.Side(req.Side),
+		Type:        orderType,
+		Qty:         req.Qty,
+		LimitPrice:  req.LimitPrice,
+		TimeInForce: timeInForce,
+	})
+	if err != nil {
+		log.Printf("Error submitting order for %s: %v", req.Symbol, err)
+		http.Error(w, "Error submitting order: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(order); err != nil {
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
+
+// CancelOrderAPIHandler handles DELETE /api/orders/{id}, gracefully
+// canceling a single open order.
+func CancelOrderAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if orderExecutor == nil {
+		http.Error(w, "Trading is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	orderID := mux.Vars(r)["id"]
+	if orderID == "" {
+		http.Error(w, "Order id required", http.StatusBadRequest)
+		return
+	}
+
+	order, ok := orderExecutor.Book().FindByID(orderID)
+	if !ok {
+		http.Error(w, "Order not found or already closed", http.StatusNotFound)
+		return
+	}
+
+	if err := orderExecutor.GracefulCancel(r.Context(), order); err != nil {
+		log.Printf("Error canceling order %s: %v", orderID, err)
+		http.Error(w, "Error canceling order: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
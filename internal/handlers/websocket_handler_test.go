@@ -1,9 +1,9 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -11,14 +11,20 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-// TestWebSocketConnection tests the WebSocket connection and initial data
+// TestWebSocketConnection exercises the multiplexed /ws/stocks protocol: a
+// client subscribes to the trades and technicals channels for a ticker,
+// gets acked, then receives the pushes triggered for that ticker.
+// INVESTIFY_PROVIDER=sim keeps this deterministic and network-free, the
+// same simulated data the handler served before real providers were wired
+// in.
 func TestWebSocketConnection(t *testing.T) {
-	// Initialize the handler
+	os.Setenv("INVESTIFY_PROVIDER", "sim")
+	defer os.Unsetenv("INVESTIFY_PROVIDER")
+
 	InitWebSocketHandler()
 
-	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/ws/stocks/") {
+		if strings.HasPrefix(r.URL.Path, "/ws/stocks") {
 			HandleWebSocket(w, r)
 		} else {
 			http.NotFound(w, r)
@@ -26,86 +32,88 @@ func TestWebSocketConnection(t *testing.T) {
 	}))
 	defer server.Close()
 
-	// Convert http to ws URL
-	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/stocks/AAPL"
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/stocks"
 
-	// Connect to WebSocket
 	ws, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
 	if err != nil {
 		t.Fatalf("Failed to connect to WebSocket: %v", err)
 	}
 	defer ws.Close()
-
-	// Wait for and read the initial message
 	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
-	_, message, err := ws.ReadMessage()
-	if err != nil {
-		t.Fatalf("Failed to read WebSocket message: %v", err)
-	}
 
-	// Parse the JSON message
-	var update StockUpdate
-	if err := json.Unmarshal(message, &update); err != nil {
-		t.Fatalf("Failed to unmarshal message: %v", err)
-	}
-	
-	// Verify the update contains technical indicators data
-	if update.Technical == nil {
-		t.Error("Technical indicators data is missing from WebSocket update")
-	} else {
-		// Check for required technical indicators
-		requiredFields := []string{"dates", "rsi", "macd", "macd_signal", "macd_histogram", "bollinger_middle", "bollinger_upper", "bollinger_lower"}
-		for _, field := range requiredFields {
-			if _, exists := update.Technical[field]; !exists {
-				t.Errorf("Required technical indicator '%s' is missing", field)
-			}
-		}
+	if err := ws.WriteJSON(controlFrame{
+		Action:     "subscribe",
+		Trades:     []string{"AAPL"},
+		Technicals: []string{"AAPL"},
+	}); err != nil {
+		t.Fatalf("Failed to send subscribe frame: %v", err)
 	}
 
-	// Verify the message contents
-	if update.Ticker != "AAPL" {
-		t.Errorf("Expected ticker AAPL, got %s", update.Ticker)
+	var ack subscriptionAck
+	if err := ws.ReadJSON(&ack); err != nil {
+		t.Fatalf("Failed to read subscription ack: %v", err)
 	}
-	if update.Price <= 0 {
-		t.Errorf("Expected positive price, got %f", update.Price)
+	if ack.Type != "subscription" {
+		t.Errorf("Expected ack type 'subscription', got %q", ack.Type)
 	}
-	if update.LastUpdated == "" {
-		t.Error("Expected LastUpdated timestamp to be set")
+	if len(ack.Trades) != 1 || ack.Trades[0] != "AAPL" {
+		t.Errorf("Expected trades subscription [AAPL], got %v", ack.Trades)
 	}
 
-	// Check for technical indicators
-	if update.Technical == nil {
-		t.Error("Expected technical indicators to be present")
-	} else {
-		// Verify key technical indicators
-		if rsi, ok := update.Technical["rsi"]; !ok {
-			t.Error("RSI data missing from technical indicators")
-		} else if rsiSlice, isSlice := rsi.([]interface{}); !isSlice || len(rsiSlice) == 0 {
-			t.Error("RSI data should be a non-empty slice")
-		}
+	// Trigger pushes directly rather than waiting for the broadcaster's
+	// poll interval.
+	go broadcastTrade("AAPL")
+	go broadcastTechnicals("AAPL")
 
-		if macd, ok := update.Technical["macd"]; !ok {
-			t.Error("MACD data missing from technical indicators")
-		} else if macdSlice, isSlice := macd.([]interface{}); !isSlice || len(macdSlice) == 0 {
-			t.Error("MACD data should be a non-empty slice")
+	gotTrade, gotTechnicals := false, false
+	for i := 0; i < 2; i++ {
+		var raw map[string]interface{}
+		if err := ws.ReadJSON(&raw); err != nil {
+			t.Fatalf("Failed to read push message: %v", err)
 		}
 
-		if bbMiddle, ok := update.Technical["bollinger_middle"]; !ok {
-			t.Error("Bollinger middle band data missing from technical indicators")
-		} else if bbSlice, isSlice := bbMiddle.([]interface{}); !isSlice || len(bbSlice) == 0 {
-			t.Error("Bollinger band data should be a non-empty slice")
+		switch raw["T"] {
+		case "t":
+			gotTrade = true
+			if raw["S"] != "AAPL" {
+				t.Errorf("Expected trade ticker AAPL, got %v", raw["S"])
+			}
+			if price, ok := raw["price"].(float64); !ok || price <= 0 {
+				t.Errorf("Expected positive trade price, got %v", raw["price"])
+			}
+		case "tech":
+			gotTechnicals = true
+			technical, ok := raw["technical"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("Expected technical field to be an object, got %v", raw["technical"])
+			}
+			requiredFields := []string{"dates", "rsi", "macd", "macd_signal", "macd_histogram", "bollinger_middle", "bollinger_upper", "bollinger_lower"}
+			for _, field := range requiredFields {
+				if _, exists := technical[field]; !exists {
+					t.Errorf("Required technical indicator %q is missing", field)
+				}
+			}
+		default:
+			t.Errorf("Unexpected message type %v", raw["T"])
 		}
 	}
+
+	if !gotTrade {
+		t.Error("Did not receive a trade push")
+	}
+	if !gotTechnicals {
+		t.Error("Did not receive a technicals push")
+	}
 }
 
 // TestWebSocketBroadcaster tests the broadcaster functionality
 func TestWebSocketBroadcaster(t *testing.T) {
 	// This is a simple test to verify the broadcaster starts without panicking
 	// For a real test, we'd need to wait for broadcasts and check them
-	
+
 	// Initialize the handler
 	InitWebSocketHandler()
-	
+
 	// Start the broadcaster and wait a moment
 	done := make(chan bool)
 	go func() {
@@ -113,7 +121,7 @@ func TestWebSocketBroadcaster(t *testing.T) {
 		time.Sleep(2 * time.Second)
 		done <- true
 	}()
-	
+
 	select {
 	case <-done:
 		// Broadcaster started successfully
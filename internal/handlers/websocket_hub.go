@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"log"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/martinrizk/investify/internal/services"
+)
+
+// wsChannel names one of the subscribable data channels on the /ws/stocks
+// multiplexed feed.
+type wsChannel string
+
+const (
+	channelTrades     wsChannel = "trades"
+	channelBars       wsChannel = "bars"
+	channelTechnicals wsChannel = "technicals"
+	channelOrders     wsChannel = "orders"
+	channelAlerts     wsChannel = "alerts"
+)
+
+// clientSendBuffer bounds how many outgoing messages a client's writer
+// goroutine will queue before the slow-client drop policy kicks in.
+const clientSendBuffer = 32
+
+// controlFrame is a client->server control message on /ws/stocks, e.g.
+// {"action":"subscribe","trades":["AAPL","MSFT"],"bars":["TSLA"]}.
+type controlFrame struct {
+	Action     string   `json:"action"`
+	Trades     []string `json:"trades,omitempty"`
+	Bars       []string `json:"bars,omitempty"`
+	Technicals []string `json:"technicals,omitempty"`
+	Orders     []string `json:"orders,omitempty"`
+	Alerts     []string `json:"alerts,omitempty"`
+}
+
+// subscriptionAck is the server's response to a subscribe/unsubscribe
+// control frame, reflecting the client's full subscription set afterward.
+type subscriptionAck struct {
+	Type       string   `json:"T"`
+	Trades     []string `json:"trades"`
+	Bars       []string `json:"bars"`
+	Technicals []string `json:"technicals"`
+	Orders     []string `json:"orders"`
+	Alerts     []string `json:"alerts"`
+}
+
+// tradeMessage is a "T":"t" push for one ticker's latest trade price.
+type tradeMessage struct {
+	Type      string  `json:"T"`
+	Ticker    string  `json:"S"`
+	Price     float64 `json:"price"`
+	Change    float64 `json:"change"`
+	ChangePct string  `json:"change_pct"`
+	Timestamp string  `json:"timestamp"`
+}
+
+// barMessage is a "T":"b" push for one ticker's latest OHLCV bar.
+type barMessage struct {
+	Type      string  `json:"T"`
+	Ticker    string  `json:"S"`
+	Open      float64 `json:"o"`
+	High      float64 `json:"h"`
+	Low       float64 `json:"l"`
+	Close     float64 `json:"c"`
+	Volume    int64   `json:"v"`
+	Timestamp string  `json:"t"`
+}
+
+// technicalsMessage is a "T":"tech" push of indicator data for one ticker.
+type technicalsMessage struct {
+	Type      string                 `json:"T"`
+	Ticker    string                 `json:"S"`
+	Technical map[string]interface{} `json:"technical"`
+}
+
+// orderMessage is a "T":"order" push of an order's current state, sent to
+// the orders channel's subscribers for that order's symbol whenever it's
+// submitted, canceled, or otherwise updated.
+type orderMessage struct {
+	Type           string  `json:"T"`
+	Ticker         string  `json:"S"`
+	OrderID        string  `json:"order_id"`
+	Side           string  `json:"side"`
+	OrderType      string  `json:"order_type"`
+	Qty            float64 `json:"qty"`
+	FilledQty      float64 `json:"filled_qty"`
+	FilledAvgPrice float64 `json:"filled_avg_price"`
+	Status         string  `json:"status"`
+	Event          string  `json:"event"`
+}
+
+// alertMessage is a "T":"alert" push when a PriceAlertMonitor stop fires
+// for one ticker, sent to the alerts channel's subscribers for that
+// ticker.
+type alertMessage struct {
+	Type   string  `json:"T"`
+	Ticker string  `json:"S"`
+	Side   string  `json:"side"`
+	Price  float64 `json:"price"`
+	Reason string  `json:"reason"`
+}
+
+// client is one connected /ws/stocks WebSocket, with its own outbound send
+// queue (so one slow reader can't stall writes to everyone else) and the
+// set of channel/ticker pairs it's currently subscribed to.
+type client struct {
+	conn *websocket.Conn
+	send chan interface{}
+
+	subsMu sync.Mutex
+	subs   map[wsChannel]map[string]bool
+}
+
+func newClient(conn *websocket.Conn) *client {
+	return &client{
+		conn: conn,
+		send: make(chan interface{}, clientSendBuffer),
+		subs: map[wsChannel]map[string]bool{
+			channelTrades:     {},
+			channelBars:       {},
+			channelTechnicals: {},
+			channelOrders:     {},
+			channelAlerts:     {},
+		},
+	}
+}
+
+// tickers returns c's current subscriptions for ch, sorted-stable isn't
+// required since these are only ever used for fan-out and acks.
+func (c *client) tickers(ch wsChannel) []string {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	out := make([]string, 0, len(c.subs[ch]))
+	for ticker := range c.subs[ch] {
+		out = append(out, ticker)
+	}
+	return out
+}
+
+// enqueue pushes msg to c's send queue, dropping it if the client isn't
+// keeping up rather than blocking the broadcaster on one slow connection.
+func (c *client) enqueue(msg interface{}) {
+	select {
+	case c.send <- msg:
+		services.IncWSMessagesSent()
+	default:
+		services.IncSlowClientDrops()
+		log.Printf("websocket: dropping message for slow client (buffer full)")
+	}
+}
+
+// Hub tracks every connected /ws/stocks client and an inverse
+// channel->ticker->clients index, so a price update for one ticker fans out
+// in O(subscribers) instead of every update being checked against every
+// connection.
+type Hub struct {
+	mu          sync.RWMutex
+	clients     map[*client]bool
+	subscribers map[wsChannel]map[string]map[*client]bool
+}
+
+func newHub() *Hub {
+	return &Hub{
+		clients: make(map[*client]bool),
+		subscribers: map[wsChannel]map[string]map[*client]bool{
+			channelTrades:     make(map[string]map[*client]bool),
+			channelBars:       make(map[string]map[*client]bool),
+			channelTechnicals: make(map[string]map[*client]bool),
+			channelOrders:     make(map[string]map[*client]bool),
+			channelAlerts:     make(map[string]map[*client]bool),
+		},
+	}
+}
+
+// hub is the single Hub backing HandleWebSocket.
+var hub = newHub()
+
+// register adds a newly connected client.
+func (h *Hub) register(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[c] = true
+}
+
+// unregister removes c and every subscription it held.
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.clients, c)
+	for ch, tickers := range c.subs {
+		for ticker := range tickers {
+			if set := h.subscribers[ch][ticker]; set != nil {
+				delete(set, c)
+				if len(set) == 0 {
+					delete(h.subscribers[ch], ticker)
+				}
+			}
+		}
+	}
+}
+
+// subscribe adds tickers to c's subscription set for ch and indexes c
+// under each ticker in the hub's inverse map.
+func (h *Hub) subscribe(c *client, ch wsChannel, tickers []string) {
+	if len(tickers) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	for _, ticker := range tickers {
+		c.subs[ch][ticker] = true
+
+		if h.subscribers[ch][ticker] == nil {
+			h.subscribers[ch][ticker] = make(map[*client]bool)
+		}
+		h.subscribers[ch][ticker][c] = true
+	}
+}
+
+// unsubscribe removes tickers from c's subscription set for ch.
+func (h *Hub) unsubscribe(c *client, ch wsChannel, tickers []string) {
+	if len(tickers) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	for _, ticker := range tickers {
+		delete(c.subs[ch], ticker)
+
+		if set := h.subscribers[ch][ticker]; set != nil {
+			delete(set, c)
+			if len(set) == 0 {
+				delete(h.subscribers[ch], ticker)
+			}
+		}
+	}
+}
+
+// tickersForChannel returns the de-duplicated set of tickers with at least
+// one subscriber on ch, so the broadcaster only fetches data that's
+// actually needed.
+func (h *Hub) tickersForChannel(ch wsChannel) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	tickers := make([]string, 0, len(h.subscribers[ch]))
+	for ticker := range h.subscribers[ch] {
+		tickers = append(tickers, ticker)
+	}
+	return tickers
+}
+
+// broadcast delivers msg to every client subscribed to ticker on ch.
+func (h *Hub) broadcast(ch wsChannel, ticker string, msg interface{}) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.subscribers[ch][ticker] {
+		c.enqueue(msg)
+	}
+}
@@ -0,0 +1,332 @@
+// Package indicators computes standard technical indicators (moving
+// averages, RSI, MACD, Bollinger Bands, 52-week range) from a series of
+// closing prices, and turns the concrete crossings in that series into
+// human-readable signal strings the ML predictor can cite as key factors.
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// Result holds every indicator computed from a price series, evaluated as of
+// the most recent close.
+type Result struct {
+	SMA20  float64
+	SMA50  float64
+	SMA200 float64
+	EMA12  float64
+	EMA26  float64
+
+	RSI14 float64
+
+	MACD          float64
+	MACDSignal    float64
+	MACDHistogram float64
+
+	BollingerUpper  float64
+	BollingerMiddle float64
+	BollingerLower  float64
+
+	FiftyTwoWeekHigh float64
+	FiftyTwoWeekLow  float64
+
+	macdBullishCross bool
+	macdBearishCross bool
+}
+
+// minClosesForCompute is the shortest series Compute can derive a MACD
+// signal line from: 26 for the slow EMA, plus 9 more so the signal line
+// itself has settled.
+const minClosesForCompute = 35
+
+// Compute derives Result from a chronologically ordered (oldest first)
+// series of daily closes, alongside the highs/lows covering the same
+// window for the 52-week range. highs and lows must be the same length as
+// closes.
+func Compute(closes, highs, lows []float64) (*Result, error) {
+	if len(closes) < minClosesForCompute {
+		return nil, fmt.Errorf("indicators: need at least %d closes, got %d", minClosesForCompute, len(closes))
+	}
+	if len(highs) != len(closes) || len(lows) != len(closes) {
+		return nil, fmt.Errorf("indicators: highs/lows must match closes length")
+	}
+
+	ema12Series := emaSeries(closes, 12)
+	ema26Series := emaSeries(closes, 26)
+
+	macdSeries := make([]float64, len(closes))
+	for i := range closes {
+		macdSeries[i] = ema12Series[i] - ema26Series[i]
+	}
+	signalSeries := emaSeries(macdSeries, 9)
+
+	last := len(closes) - 1
+	r := &Result{
+		SMA20:  sma(closes, 20),
+		SMA50:  sma(closes, 50),
+		SMA200: sma(closes, 200),
+		EMA12:  ema12Series[last],
+		EMA26:  ema26Series[last],
+		RSI14:  rsi(closes, 14),
+
+		MACD:          macdSeries[last],
+		MACDSignal:    signalSeries[last],
+		MACDHistogram: macdSeries[last] - signalSeries[last],
+
+		FiftyTwoWeekHigh: maxOf(highs),
+		FiftyTwoWeekLow:  minOf(lows),
+	}
+	r.BollingerUpper, r.BollingerMiddle, r.BollingerLower = bollingerBands(closes, 20, 2.0)
+
+	prevHistogram := macdSeries[last-1] - signalSeries[last-1]
+	r.macdBullishCross = prevHistogram <= 0 && r.MACDHistogram > 0
+	r.macdBearishCross = prevHistogram >= 0 && r.MACDHistogram < 0
+
+	return r, nil
+}
+
+// Signals describes the concrete indicator crossings a human (or the ML
+// predictor's KeyFactors) would cite, e.g. "MACD bullish crossover" or
+// "RSI oversold at 28".
+func (r *Result) Signals(latestClose float64) []string {
+	var signals []string
+
+	if r.macdBullishCross {
+		signals = append(signals, "MACD bullish crossover")
+	} else if r.macdBearishCross {
+		signals = append(signals, "MACD bearish crossover")
+	}
+
+	switch {
+	case r.RSI14 <= 30:
+		signals = append(signals, fmt.Sprintf("RSI oversold at %.0f", r.RSI14))
+	case r.RSI14 >= 70:
+		signals = append(signals, fmt.Sprintf("RSI overbought at %.0f", r.RSI14))
+	}
+
+	if r.SMA20 > r.SMA50 {
+		signals = append(signals, "20-day SMA above 50-day SMA")
+	} else if r.SMA20 < r.SMA50 {
+		signals = append(signals, "20-day SMA below 50-day SMA")
+	}
+
+	if r.SMA200 > 0 {
+		if r.SMA50 > r.SMA200 {
+			signals = append(signals, "50-day SMA above 200-day SMA (golden cross regime)")
+		} else if r.SMA50 < r.SMA200 {
+			signals = append(signals, "50-day SMA below 200-day SMA (death cross regime)")
+		}
+	}
+
+	switch {
+	case latestClose >= r.BollingerUpper:
+		signals = append(signals, "Price broke above upper Bollinger Band")
+	case latestClose <= r.BollingerLower:
+		signals = append(signals, "Price broke below lower Bollinger Band")
+	}
+
+	if r.FiftyTwoWeekHigh > 0 && latestClose >= r.FiftyTwoWeekHigh*0.98 {
+		signals = append(signals, "Price near 52-week high")
+	} else if r.FiftyTwoWeekLow > 0 && latestClose <= r.FiftyTwoWeekLow*1.02 {
+		signals = append(signals, "Price near 52-week low")
+	}
+
+	return signals
+}
+
+// sma returns the simple moving average of the last window values, or 0 if
+// there aren't enough values.
+func sma(values []float64, window int) float64 {
+	if len(values) < window {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values[len(values)-window:] {
+		sum += v
+	}
+	return sum / float64(window)
+}
+
+// emaSeries returns the exponential moving average of values at every
+// index, seeded with a simple average of the first window values.
+func emaSeries(values []float64, window int) []float64 {
+	series := make([]float64, len(values))
+	if len(values) == 0 {
+		return series
+	}
+
+	multiplier := 2.0 / float64(window+1)
+	seed := sma(values[:min(window, len(values))], min(window, len(values)))
+	series[0] = seed
+	prev := seed
+	for i := 1; i < len(values); i++ {
+		prev = (values[i]-prev)*multiplier + prev
+		series[i] = prev
+	}
+	return series
+}
+
+// rsi computes the Relative Strength Index using Wilder's smoothing over
+// the last window+1 closes.
+func rsi(values []float64, window int) float64 {
+	if len(values) < window+1 {
+		return 50
+	}
+
+	start := len(values) - window - 1
+	var gainSum, lossSum float64
+	for i := start + 1; i <= start+window; i++ {
+		delta := values[i] - values[i-1]
+		if delta > 0 {
+			gainSum += delta
+		} else {
+			lossSum -= delta
+		}
+	}
+	avgGain := gainSum / float64(window)
+	avgLoss := lossSum / float64(window)
+
+	if avgLoss == 0 {
+		return 100
+	}
+	rs := avgGain / avgLoss
+	return 100 - (100 / (1 + rs))
+}
+
+// bollingerBands returns the upper, middle (SMA), and lower bands for the
+// last window values, numStdDev standard deviations from the mean.
+func bollingerBands(values []float64, window int, numStdDev float64) (upper, middle, lower float64) {
+	middle = sma(values, window)
+	if len(values) < window {
+		return middle, middle, middle
+	}
+
+	window64 := float64(window)
+	var variance float64
+	for _, v := range values[len(values)-window:] {
+		diff := v - middle
+		variance += diff * diff
+	}
+	stdDev := math.Sqrt(variance / window64)
+
+	upper = middle + numStdDev*stdDev
+	lower = middle - numStdDev*stdDev
+	return upper, middle, lower
+}
+
+func maxOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	max := values[0]
+	for _, v := range values[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+func minOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// StdDev returns the population standard deviation of values.
+func StdDev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	mean := sma(values, len(values))
+	var variance float64
+	for _, v := range values {
+		diff := v - mean
+		variance += diff * diff
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
+
+// ATR computes the Average True Range over the last window periods from
+// highs/lows/closes (all the same length, chronological oldest-first). The
+// true range for period i is max(high-low, |high-prevClose|,
+// |low-prevClose|).
+func ATR(highs, lows, closes []float64, window int) float64 {
+	if window < 1 || len(highs) < window+1 || len(lows) != len(highs) || len(closes) != len(highs) {
+		return 0
+	}
+
+	start := len(highs) - window
+	trueRanges := make([]float64, 0, window)
+	for i := start; i < len(highs); i++ {
+		high, low, prevClose := highs[i], lows[i], closes[i-1]
+		tr := math.Max(high-low, math.Max(math.Abs(high-prevClose), math.Abs(low-prevClose)))
+		trueRanges = append(trueRanges, tr)
+	}
+	return sma(trueRanges, len(trueRanges))
+}
+
+// fisherClamp keeps FisherTransform's normalized input away from +/-1,
+// where 0.5*ln((1+x)/(1-x)) has a singularity.
+const fisherClamp = 0.999
+
+// FisherTransform computes Ehlers' Fisher Transform of the latest value in
+// values, normalized to [-1,1] relative to the min/max over the trailing
+// window, then y = 0.5*ln((1+x)/(1-x)).
+func FisherTransform(values []float64, window int) float64 {
+	if window < 2 || len(values) < window {
+		return 0
+	}
+
+	recent := values[len(values)-window:]
+	lo, hi := minOf(recent), maxOf(recent)
+	if hi == lo {
+		return 0
+	}
+
+	x := 2*((values[len(values)-1]-lo)/(hi-lo)) - 1
+	x = math.Max(-fisherClamp, math.Min(fisherClamp, x))
+	return 0.5 * math.Log((1+x)/(1-x))
+}
+
+// Drift computes the SMA of log-returns over window divided by their
+// standard deviation: a directional-strength signal that discounts noisy
+// series the way a Sharpe ratio discounts volatile returns.
+func Drift(closes []float64, window int) float64 {
+	if window < 1 || len(closes) < window+1 {
+		return 0
+	}
+
+	start := len(closes) - window
+	logReturns := make([]float64, 0, window)
+	for i := start; i < len(closes); i++ {
+		if closes[i-1] <= 0 || closes[i] <= 0 {
+			continue
+		}
+		logReturns = append(logReturns, math.Log(closes[i]/closes[i-1]))
+	}
+	if len(logReturns) == 0 {
+		return 0
+	}
+
+	stdDev := StdDev(logReturns)
+	if stdDev == 0 {
+		return 0
+	}
+	return sma(logReturns, len(logReturns)) / stdDev
+}
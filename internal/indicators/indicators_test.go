@@ -0,0 +1,81 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// assertClose fails t if got and want differ by more than tol.
+func assertClose(t *testing.T, name string, got, want, tol float64) {
+	t.Helper()
+	if math.Abs(got-want) > tol {
+		t.Errorf("%s = %v, want %v (+/- %v)", name, got, want, tol)
+	}
+}
+
+// wilderRSICloses is Wilder's original 15-close example series from "New
+// Concepts in Technical Trading Systems", used to pin down rsi's first
+// (unsmoothed) value.
+var wilderRSICloses = []float64{
+	44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+	45.89, 46.03, 45.61, 46.28, 46.28,
+}
+
+func TestRSIWilderReference(t *testing.T) {
+	assertClose(t, "rsi(wilderRSICloses, 14)", rsi(wilderRSICloses, 14), 70.4641, 0.001)
+}
+
+func TestRSINeedsWindowPlusOneCloses(t *testing.T) {
+	if got := rsi(wilderRSICloses[:14], 14); got != 50 {
+		t.Errorf("rsi with only 14 closes = %v, want neutral 50", got)
+	}
+}
+
+// macdFixtureCloses is a 40-day synthetic series (sawtooth-on-an-uptrend)
+// long enough for Compute's minClosesForCompute, with a known MACD/signal
+// pair below used to catch regressions in emaSeries or the MACD wiring.
+var macdFixtureCloses = []float64{
+	101.0, 100.0, 100.5, 102.5, 101.5, 102.0, 104.0, 103.0, 103.5, 105.5,
+	104.5, 105.0, 107.0, 106.0, 106.5, 108.5, 107.5, 108.0, 110.0, 109.0,
+	109.5, 111.5, 110.5, 111.0, 113.0, 112.0, 112.5, 114.5, 113.5, 114.0,
+	116.0, 115.0, 115.5, 117.5, 116.5, 117.0, 119.0, 118.0, 118.5, 120.5,
+}
+
+func TestComputeMACDFixture(t *testing.T) {
+	highs := make([]float64, len(macdFixtureCloses))
+	lows := make([]float64, len(macdFixtureCloses))
+	for i, c := range macdFixtureCloses {
+		highs[i] = c + 1
+		lows[i] = c - 1
+	}
+
+	result, err := Compute(macdFixtureCloses, highs, lows)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+
+	assertClose(t, "EMA12", result.EMA12, 116.84346872065657, 1e-6)
+	assertClose(t, "EMA26", result.EMA26, 113.9093734495828, 1e-6)
+	assertClose(t, "MACD", result.MACD, 2.9340952710737724, 1e-6)
+	assertClose(t, "MACDSignal", result.MACDSignal, 2.619743718036784, 1e-6)
+	assertClose(t, "MACDHistogram", result.MACDHistogram, 0.3143515530369885, 1e-6)
+	assertClose(t, "SMA20", result.SMA20, 114.775, 1e-9)
+	assertClose(t, "BollingerUpper", result.BollingerUpper, 120.80388878650122, 1e-6)
+	assertClose(t, "BollingerLower", result.BollingerLower, 108.74611121349879, 1e-6)
+
+	if result.SMA200 != 0 {
+		t.Errorf("SMA200 = %v, want 0 with only %d closes", result.SMA200, len(macdFixtureCloses))
+	}
+}
+
+func TestATRFixture(t *testing.T) {
+	highs := make([]float64, len(macdFixtureCloses))
+	lows := make([]float64, len(macdFixtureCloses))
+	for i, c := range macdFixtureCloses {
+		highs[i] = c + 1
+		lows[i] = c - 1
+	}
+
+	got := ATR(highs, lows, macdFixtureCloses, 14)
+	assertClose(t, "ATR14", got, 2.357142857142857, 1e-9)
+}
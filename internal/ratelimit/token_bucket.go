@@ -0,0 +1,62 @@
+// Package ratelimit implements a small token-bucket limiter for capping how
+// often a caller may proceed, used by the history-fetch pipeline to avoid
+// overwhelming rate-limited upstream market-data providers.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket allows up to burst immediate calls, then refills at
+// ratePerMinute tokens per minute thereafter.
+type TokenBucket struct {
+	mu         sync.Mutex
+	tokens     float64
+	maxTokens  float64
+	refillRate float64 // tokens per second
+	lastRefill time.Time
+}
+
+// New creates a TokenBucket that allows burst immediate calls (at least 1)
+// and refills at ratePerMinute tokens per minute after that.
+func New(ratePerMinute, burst int) *TokenBucket {
+	if burst <= 0 {
+		burst = 1
+	}
+	if ratePerMinute <= 0 {
+		ratePerMinute = 1
+	}
+	return &TokenBucket{
+		tokens:     float64(burst),
+		maxTokens:  float64(burst),
+		refillRate: float64(ratePerMinute) / 60.0,
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available, then consumes it.
+func (tb *TokenBucket) Wait() {
+	for {
+		tb.mu.Lock()
+		tb.refill()
+		if tb.tokens >= 1 {
+			tb.tokens--
+			tb.mu.Unlock()
+			return
+		}
+		wait := time.Duration((1 - tb.tokens) / tb.refillRate * float64(time.Second))
+		tb.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+// refill tops tokens up based on elapsed time since the last refill, capped
+// at maxTokens. Callers must hold tb.mu.
+func (tb *TokenBucket) refill() {
+	now := time.Now()
+	elapsed := now.Sub(tb.lastRefill).Seconds()
+	tb.tokens = math.Min(tb.maxTokens, tb.tokens+elapsed*tb.refillRate)
+	tb.lastRefill = now
+}
@@ -1,59 +1,81 @@
 package services
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
-	"strings"
 	"time"
 )
 
-// AIService handles AI-based stock recommendations
-type AIService struct {
-	openAIKey string
+// Provider is one LLM backend AIService can get a stock recommendation
+// from: OpenAIProvider, AnthropicProvider, CompatibleProvider (a generic
+// OpenAI-compatible endpoint for Ollama/LM Studio/Groq/etc.), or
+// NullProvider, the rule-based engine used as the guaranteed last resort.
+type Provider interface {
+	Name() string
+	Complete(ctx context.Context, systemPrompt, userPrompt string, maxTokens int) (string, error)
 }
 
-// OpenAIRequest represents the request to OpenAI API
-type OpenAIRequest struct {
-	Model     string    `json:"model"`
-	Messages  []Message `json:"messages"`
-	MaxTokens int       `json:"max_tokens"`
-}
+// aiCompletionMaxTokens bounds every provider's response to roughly the
+// length GetStockRecommendation's prompt asks for.
+const aiCompletionMaxTokens = 150
 
-type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
+// aiRequestTimeout bounds how long a single provider is given to answer
+// before AIService falls through to the next one.
+const aiRequestTimeout = 30 * time.Second
 
-// OpenAIResponse represents the response from OpenAI API
-type OpenAIResponse struct {
-	Choices []struct {
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
-	} `json:"choices"`
+// AIService generates stock recommendations by trying each configured
+// Provider in order, falling through to the next on error (including
+// timeouts, 429s, and 401s), and logging which provider ultimately
+// answered.
+type AIService struct {
+	providers []Provider
 }
 
-// NewAIService creates a new AI service instance
-func NewAIService(openAIKey string) *AIService {
-	return &AIService{
-		openAIKey: openAIKey,
-	}
+// NewAIService creates an AIService that tries providers in the given
+// order. Callers should include a NullProvider last so there's always an
+// answer; buildDefaultAIProviders does this for the env-configured chain
+// used in production.
+func NewAIService(providers []Provider) *AIService {
+	return &AIService{providers: providers}
 }
 
-// GetStockRecommendation generates an AI-based stock recommendation
+// GetStockRecommendation generates a recommendation by trying each
+// configured provider in turn, returning the first one that answers
+// without error.
 func (ai *AIService) GetStockRecommendation(stock *StockInfo) (string, error) {
-	// If no OpenAI key is provided, use rule-based recommendation
-	if ai.openAIKey == "" {
-		return ai.getRuleBasedRecommendation(stock), nil
+	systemPrompt := "You are a financial advisor providing stock recommendations based on market data."
+	userPrompt := stockRecommendationPrompt(stock)
+
+	for _, provider := range ai.providers {
+		// NullProvider's Complete can't see stock's structured fields (only
+		// the rendered prompt text), so route straight to the real rule
+		// engine instead of the generic text completion path.
+		if _, isNull := provider.(NullProvider); isNull {
+			log.Printf("AI recommendation answered by provider %q", provider.Name())
+			return ai.getRuleBasedRecommendation(stock), nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), aiRequestTimeout)
+		recommendation, err := provider.Complete(ctx, systemPrompt, userPrompt, aiCompletionMaxTokens)
+		cancel()
+		if err == nil {
+			log.Printf("AI recommendation answered by provider %q", provider.Name())
+			return recommendation, nil
+		}
+		log.Printf("AI provider %q failed, falling through: %v", provider.Name(), err)
 	}
 
-	// Create prompt for OpenAI
-	prompt := fmt.Sprintf(`Analyze this stock and provide a recommendation (BUY, SELL, or HOLD) with a brief explanation:
-	
+	// Every provider failed (or none were configured); the rule-based
+	// fallback below never errors, so this always produces an answer.
+	return ai.getRuleBasedRecommendation(stock), nil
+}
+
+// stockRecommendationPrompt builds the prompt asking an LLM provider for a
+// BUY/SELL/HOLD recommendation on stock.
+func stockRecommendationPrompt(stock *StockInfo) string {
+	return fmt.Sprintf(`Analyze this stock and provide a recommendation (BUY, SELL, or HOLD) with a brief explanation:
+
 Stock: %s (%s)
 Current Price: $%.2f
 Daily Change: $%.2f (%s)
@@ -66,89 +88,18 @@ Market Cap: %s
 Please provide a concise recommendation with reasoning based on the data provided.`,
 		stock.CompanyName, stock.Ticker, stock.Price, stock.Change, stock.ChangePct,
 		stock.Open, stock.High, stock.Low, stock.Volume, stock.MarketCap)
-
-	// Make request to OpenAI
-	reqBody := OpenAIRequest{
-		Model: "gpt-3.5-turbo",
-		Messages: []Message{
-			{
-				Role:    "system",
-				Content: "You are a financial advisor providing stock recommendations based on market data.",
-			},
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-		MaxTokens: 150,
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return ai.getRuleBasedRecommendation(stock), nil
-	}
-
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return ai.getRuleBasedRecommendation(stock), nil
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+ai.openAIKey)
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("OpenAI API request failed: %v", err)
-		// Check for specific network errors
-		if strings.Contains(err.Error(), "timeout") {
-			log.Printf("OpenAI API timeout - falling back to rule-based recommendation")
-		} else if strings.Contains(err.Error(), "no such host") || strings.Contains(err.Error(), "lookup") {
-			log.Printf("OpenAI API network connectivity issue - falling back to rule-based recommendation")
-		}
-		return ai.getRuleBasedRecommendation(stock), nil
-	}
-	defer resp.Body.Close()
-	
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("OpenAI API returned non-200 status code: %d", resp.StatusCode)
-		
-		// Handle specific error codes
-		switch resp.StatusCode {
-		case http.StatusTooManyRequests:
-			log.Printf("OpenAI rate limit exceeded - falling back to rule-based recommendation")
-		case http.StatusUnauthorized:
-			log.Printf("OpenAI API key invalid or expired - falling back to rule-based recommendation")
-		default:
-			log.Printf("OpenAI API error with status code %d - falling back to rule-based recommendation", resp.StatusCode)
-		}
-		
-		return ai.getRuleBasedRecommendation(stock), nil
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Failed to read OpenAI API response: %v", err)
-		return ai.getRuleBasedRecommendation(stock), nil
-	}
-
-	var openAIResp OpenAIResponse
-	if err := json.Unmarshal(body, &openAIResp); err != nil {
-		log.Printf("Failed to parse OpenAI API response: %v", err)
-		log.Printf("Response body: %s", string(body))
-		return ai.getRuleBasedRecommendation(stock), nil
-	}
-
-	if len(openAIResp.Choices) > 0 {
-		return openAIResp.Choices[0].Message.Content, nil
-	}
-
-	return ai.getRuleBasedRecommendation(stock), nil
 }
 
-// getRuleBasedRecommendation provides a fallback rule-based recommendation
+// getRuleBasedRecommendation provides a fallback rule-based recommendation.
+// When stock.Technical has been populated (see computeTechnicalIndicators),
+// it defers to technicalRecommendation's weighted SMA/RSI/MACD/Bollinger
+// score instead of the cruder day-range heuristic below, which only runs
+// when there isn't enough historical data to compute those indicators.
 func (ai *AIService) getRuleBasedRecommendation(stock *StockInfo) string {
+	if stock.Technical != nil {
+		return technicalRecommendation(stock)
+	}
+
 	changeFloat := stock.Change
 	changePct := calculateChangePercentage(stock.Change, stock.Price)
 
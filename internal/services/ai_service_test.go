@@ -7,10 +7,10 @@ import (
 	"testing"
 )
 
-// TestAIService tests the AI service functionality
+// TestAIService tests the rule-based fallback when no LLM providers are
+// configured.
 func TestAIService(t *testing.T) {
-	// Test rule-based recommendation when no OpenAI key is provided
-	aiService := NewAIService("")
+	aiService := NewAIService([]Provider{NullProvider{}})
 	stockInfo := &StockInfo{
 		Ticker:      "AAPL",
 		CompanyName: "Apple Inc.",
@@ -24,28 +24,26 @@ func TestAIService(t *testing.T) {
 		MarketCap:   "$2.5T",
 	}
 
-	// Get recommendation without API key
 	recommendation, err := aiService.GetStockRecommendation(stockInfo)
 	if err != nil {
 		t.Errorf("Failed to get rule-based recommendation: %v", err)
 	}
-	
-	// Verify we got some recommendation
+
 	if recommendation == "" {
 		t.Errorf("Expected non-empty recommendation")
 	}
-	
-	// Check that recommendation includes BUY, SELL, or HOLD
-	if !strings.Contains(recommendation, "BUY") && 
-	   !strings.Contains(recommendation, "SELL") && 
-	   !strings.Contains(recommendation, "HOLD") {
+
+	if !strings.Contains(recommendation, "BUY") &&
+		!strings.Contains(recommendation, "SELL") &&
+		!strings.Contains(recommendation, "HOLD") {
 		t.Errorf("Recommendation should contain BUY, SELL or HOLD, got: %s", recommendation)
 	}
 }
 
-// TestOpenAIResponse tests how the service handles OpenAI API responses
-func TestOpenAIResponse(t *testing.T) {
-	// Create a mock server for OpenAI API
+// TestOpenAIProviderSuccess verifies AIService returns an OpenAIProvider's
+// answer when it succeeds, without falling through to the rule-based
+// provider behind it.
+func TestOpenAIProviderSuccess(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(`{
@@ -57,59 +55,51 @@ func TestOpenAIResponse(t *testing.T) {
 		}`))
 	}))
 	defer server.Close()
-	
-	// Create AI service with fake API key
-	aiService := NewAIService("fake-api-key")
-	
-	// Create a test stock
-	stockInfo := &StockInfo{
-		Ticker:      "AAPL",
-		CompanyName: "Apple Inc.",
-		Price:       150.25,
-		Change:      2.5,
-		ChangePct:   "1.68%",
-	}
-	
-	// Get recommendation
+
+	original := openAIChatCompletionsURL
+	openAIChatCompletionsURL = server.URL
+	defer func() { openAIChatCompletionsURL = original }()
+
+	aiService := NewAIService([]Provider{NewOpenAIProvider("fake-api-key"), NullProvider{}})
+	stockInfo := &StockInfo{Ticker: "AAPL", CompanyName: "Apple Inc.", Price: 150.25}
+
 	recommendation, err := aiService.GetStockRecommendation(stockInfo)
 	if err != nil {
 		t.Errorf("Failed to get OpenAI recommendation: %v", err)
 	}
-	
-	// Verify we got some recommendation - either from API or rule-based fallback
-	if recommendation == "" {
-		t.Errorf("Expected non-empty recommendation")
+	if recommendation != "BUY - Strong fundamentals and positive momentum." {
+		t.Errorf("Expected the OpenAI provider's answer, got: %s", recommendation)
 	}
 }
 
-// TestErrorHandling tests API error handling
-func TestErrorHandling(t *testing.T) {
-	// Create a mock server that simulates failures
+// TestAIServiceFallsThroughOnProviderError verifies a failing provider
+// doesn't prevent AIService from falling through to the next one in the
+// chain.
+func TestAIServiceFallsThroughOnProviderError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
 	defer server.Close()
-	
-	// Create AI service with fake API key
-	aiService := NewAIService("fake-api-key")
-	
-	// Create a test stock
+
+	original := openAIChatCompletionsURL
+	openAIChatCompletionsURL = server.URL
+	defer func() { openAIChatCompletionsURL = original }()
+
+	aiService := NewAIService([]Provider{NewOpenAIProvider("fake-api-key"), NullProvider{}})
 	stockInfo := &StockInfo{
 		Ticker:      "AAPL",
 		CompanyName: "Apple Inc.",
 		Price:       150.25,
+		Change:      2.5,
+		High:        152.0,
+		Low:         148.5,
 	}
-	
-	// Get recommendation - should fall back to rule-based
+
 	recommendation, err := aiService.GetStockRecommendation(stockInfo)
-	
-	// We should still get a recommendation, even if API fails
 	if err != nil {
-		t.Errorf("Expected no error when API fails (should use fallback), got: %v", err)
+		t.Errorf("Expected no error when a provider fails (should fall through), got: %v", err)
 	}
-	
-	// Verify we got some recommendation
 	if recommendation == "" {
-		t.Errorf("Expected fallback recommendation when API fails")
+		t.Errorf("Expected a fallback recommendation when the first provider fails")
 	}
 }
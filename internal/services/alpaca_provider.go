@@ -0,0 +1,511 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// AlpacaProvider implements StockDataProvider against the Alpaca Market Data
+// API. It is configured from ALPACA_KEY_ID, ALPACA_SECRET_KEY and
+// ALPACA_FEED (one of "iex" or "sip", defaults to "iex").
+type AlpacaProvider struct {
+	keyID     string
+	secretKey string
+	feed      string
+
+	httpClient *http.Client
+
+	mu         sync.Mutex
+	subscribed map[string]bool
+	streamConn *websocket.Conn
+
+	priceMu   sync.Mutex
+	lastPrice map[string]float64 // most recent streamed trade price per ticker, for computing Change/ChangePct
+}
+
+// NewAlpacaProvider builds an AlpacaProvider from the environment. It returns
+// an error if the required credentials are not configured so callers can skip
+// registering it.
+func NewAlpacaProvider() (*AlpacaProvider, error) {
+	keyID := os.Getenv("ALPACA_KEY_ID")
+	secretKey := os.Getenv("ALPACA_SECRET_KEY")
+	if keyID == "" || secretKey == "" {
+		return nil, fmt.Errorf("ALPACA_KEY_ID and ALPACA_SECRET_KEY must be set")
+	}
+
+	feed := os.Getenv("ALPACA_FEED")
+	if feed == "" {
+		feed = "iex"
+	}
+
+	return &AlpacaProvider{
+		keyID:      keyID,
+		secretKey:  secretKey,
+		feed:       feed,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		subscribed: make(map[string]bool),
+		lastPrice:  make(map[string]float64),
+	}, nil
+}
+
+// Name identifies this provider for logging and health reporting.
+func (a *AlpacaProvider) Name() string {
+	return "alpaca"
+}
+
+func (a *AlpacaProvider) authHeaders(req *http.Request) {
+	req.Header.Set("APCA-API-KEY-ID", a.keyID)
+	req.Header.Set("APCA-API-SECRET-KEY", a.secretKey)
+}
+
+// Quote returns the latest trade/quote snapshot for ticker.
+func (a *AlpacaProvider) Quote(ticker string) (*StockInfo, error) {
+	url := fmt.Sprintf("https://data.alpaca.markets/v2/stocks/%s/snapshot?feed=%s", ticker, a.feed)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.authHeaders(req)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("alpaca snapshot request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("alpaca auth or subscription-tier error: status %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("alpaca snapshot returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alpaca snapshot response: %v", err)
+	}
+
+	var snap struct {
+		LatestTrade struct {
+			Price float64 `json:"p"`
+		} `json:"latestTrade"`
+		DailyBar struct {
+			Open  float64 `json:"o"`
+			High  float64 `json:"h"`
+			Low   float64 `json:"l"`
+			Close float64 `json:"c"`
+			Vol   int64   `json:"v"`
+		} `json:"dailyBar"`
+		PrevDailyBar struct {
+			Close float64 `json:"c"`
+		} `json:"prevDailyBar"`
+	}
+
+	if err := json.Unmarshal(body, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse alpaca snapshot response: %v", err)
+	}
+
+	price := snap.LatestTrade.Price
+	if price == 0 {
+		price = snap.DailyBar.Close
+	}
+	change := price - snap.PrevDailyBar.Close
+	changePct := "0.00%"
+	if snap.PrevDailyBar.Close != 0 {
+		changePct = fmt.Sprintf("%.2f%%", (change/snap.PrevDailyBar.Close)*100)
+	}
+
+	return &StockInfo{
+		Ticker:      strings.ToUpper(ticker),
+		CompanyName: getCompanyNameFromTicker(ticker),
+		Price:       price,
+		Change:      change,
+		ChangePct:   changePct,
+		Open:        snap.DailyBar.Open,
+		High:        snap.DailyBar.High,
+		Low:         snap.DailyBar.Low,
+		Volume:      formatVolume(snap.DailyBar.Vol),
+		MarketCap:   "N/A",
+		DataAge:     0,
+	}, nil
+}
+
+// Snapshots fetches quotes for multiple tickers in a single request using
+// Alpaca's snapshots endpoint, which accepts a comma-separated symbol list.
+// It returns the tickers it successfully resolved plus the subset it
+// couldn't (e.g. due to an auth or subscription-tier error), so callers can
+// fall back to another provider for the remainder.
+func (a *AlpacaProvider) Snapshots(tickers []string) (map[string]*StockInfo, []string) {
+	if len(tickers) == 0 {
+		return nil, nil
+	}
+
+	url := fmt.Sprintf("https://data.alpaca.markets/v2/stocks/snapshots?symbols=%s&feed=%s",
+		strings.Join(tickers, ","), a.feed)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, tickers
+	}
+	a.authHeaders(req)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		log.Printf("alpaca: snapshots request failed: %v", err)
+		return nil, tickers
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("alpaca: snapshots returned status %d", resp.StatusCode)
+		return nil, tickers
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, tickers
+	}
+
+	var raw map[string]struct {
+		LatestTrade struct {
+			Price float64 `json:"p"`
+		} `json:"latestTrade"`
+		DailyBar struct {
+			Open  float64 `json:"o"`
+			High  float64 `json:"h"`
+			Low   float64 `json:"l"`
+			Close float64 `json:"c"`
+			Vol   int64   `json:"v"`
+		} `json:"dailyBar"`
+		PrevDailyBar struct {
+			Close float64 `json:"c"`
+		} `json:"prevDailyBar"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		log.Printf("alpaca: failed to parse snapshots response: %v", err)
+		return nil, tickers
+	}
+
+	results := make(map[string]*StockInfo, len(raw))
+	var missing []string
+	for _, ticker := range tickers {
+		snap, ok := raw[ticker]
+		if !ok {
+			missing = append(missing, ticker)
+			continue
+		}
+
+		price := snap.LatestTrade.Price
+		if price == 0 {
+			price = snap.DailyBar.Close
+		}
+		change := price - snap.PrevDailyBar.Close
+		changePct := "0.00%"
+		if snap.PrevDailyBar.Close != 0 {
+			changePct = fmt.Sprintf("%.2f%%", (change/snap.PrevDailyBar.Close)*100)
+		}
+
+		results[ticker] = &StockInfo{
+			Ticker:      ticker,
+			CompanyName: getCompanyNameFromTicker(ticker),
+			Price:       price,
+			Change:      change,
+			ChangePct:   changePct,
+			Open:        snap.DailyBar.Open,
+			High:        snap.DailyBar.High,
+			Low:         snap.DailyBar.Low,
+			Volume:      formatVolume(snap.DailyBar.Vol),
+			MarketCap:   "N/A",
+			DataAge:     0,
+		}
+	}
+
+	return results, missing
+}
+
+// Bars returns historical OHLCV bars for ticker between start and end.
+func (a *AlpacaProvider) Bars(ticker, timeframe string, start, end time.Time) ([]Bar, error) {
+	url := fmt.Sprintf("https://data.alpaca.markets/v2/stocks/%s/bars?timeframe=%s&start=%s&end=%s&feed=%s",
+		ticker, timeframe, start.Format(time.RFC3339), end.Format(time.RFC3339), a.feed)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.authHeaders(req)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("alpaca bars request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("alpaca bars returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alpaca bars response: %v", err)
+	}
+
+	var barsResp struct {
+		Bars []struct {
+			T string  `json:"t"`
+			O float64 `json:"o"`
+			H float64 `json:"h"`
+			L float64 `json:"l"`
+			C float64 `json:"c"`
+			V int64   `json:"v"`
+		} `json:"bars"`
+	}
+
+	if err := json.Unmarshal(body, &barsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse alpaca bars response: %v", err)
+	}
+
+	bars := make([]Bar, 0, len(barsResp.Bars))
+	for _, b := range barsResp.Bars {
+		ts, _ := time.Parse(time.RFC3339, b.T)
+		bars = append(bars, Bar{Timestamp: ts, Open: b.O, High: b.H, Low: b.L, Close: b.C, Volume: b.V})
+	}
+
+	return bars, nil
+}
+
+// Stream opens a websocket connection to Alpaca's market data stream,
+// authenticates, subscribes to trades/quotes/bars for tickers, and pushes
+// updates onto the returned channel. It reconnects with exponential backoff
+// on failure and supports dynamic (un)subscription via Subscribe/Unsubscribe.
+func (a *AlpacaProvider) Stream(ctx context.Context, tickers []string) (<-chan StreamUpdate, error) {
+	updates := make(chan StreamUpdate, 64)
+
+	a.mu.Lock()
+	for _, t := range tickers {
+		a.subscribed[strings.ToUpper(t)] = true
+	}
+	a.mu.Unlock()
+
+	go a.runStream(ctx, updates)
+
+	return updates, nil
+}
+
+// Subscribe adds tickers to the active stream's subscription set.
+func (a *AlpacaProvider) Subscribe(tickers []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, t := range tickers {
+		a.subscribed[strings.ToUpper(t)] = true
+	}
+	a.resubscribe()
+}
+
+// Unsubscribe removes tickers from the active stream's subscription set,
+// sending an explicit unsubscribe frame for them first. Alpaca's subscribe
+// protocol is additive, so without an unsubscribe frame the upstream feed
+// would keep pushing data for tickers no caller is listening for anymore.
+func (a *AlpacaProvider) Unsubscribe(tickers []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	removed := make([]string, 0, len(tickers))
+	for _, t := range tickers {
+		ticker := strings.ToUpper(t)
+		if a.subscribed[ticker] {
+			delete(a.subscribed, ticker)
+			removed = append(removed, ticker)
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+
+	if a.streamConn != nil {
+		msg := map[string]interface{}{
+			"action": "unsubscribe",
+			"trades": removed,
+			"quotes": removed,
+			"bars":   removed,
+		}
+		if err := a.streamConn.WriteJSON(msg); err != nil {
+			log.Printf("alpaca: failed to send unsubscribe message: %v", err)
+		}
+	}
+	a.resubscribe()
+}
+
+// resubscribe sends an updated subscribe message for the current ticker set.
+// Callers must hold a.mu.
+func (a *AlpacaProvider) resubscribe() {
+	if a.streamConn == nil {
+		return
+	}
+
+	tickers := make([]string, 0, len(a.subscribed))
+	for t := range a.subscribed {
+		tickers = append(tickers, t)
+	}
+
+	msg := map[string]interface{}{
+		"action": "subscribe",
+		"trades": tickers,
+		"quotes": tickers,
+		"bars":   tickers,
+	}
+	if err := a.streamConn.WriteJSON(msg); err != nil {
+		log.Printf("alpaca: failed to send subscribe message: %v", err)
+	}
+}
+
+// runStream maintains the websocket connection, reconnecting with
+// exponential backoff (capped at 30s) until ctx is cancelled.
+func (a *AlpacaProvider) runStream(ctx context.Context, updates chan<- StreamUpdate) {
+	defer close(updates)
+
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if err := a.connectAndConsume(ctx, updates); err != nil {
+			log.Printf("alpaca: stream error, reconnecting in %v: %v", backoff, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff + time.Duration(rand.Intn(250))*time.Millisecond):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// connectAndConsume dials the stream, authenticates, subscribes, and reads
+// messages until the connection drops or ctx is cancelled.
+func (a *AlpacaProvider) connectAndConsume(ctx context.Context, updates chan<- StreamUpdate) error {
+	url := fmt.Sprintf("wss://stream.data.alpaca.markets/v2/%s", a.feed)
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return fmt.Errorf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.WriteJSON(map[string]string{
+		"action": "auth",
+		"key":    a.keyID,
+		"secret": a.secretKey,
+	}); err != nil {
+		return fmt.Errorf("auth message failed: %v", err)
+	}
+
+	a.mu.Lock()
+	a.streamConn = conn
+	a.resubscribe()
+	a.mu.Unlock()
+
+	defer func() {
+		a.mu.Lock()
+		a.streamConn = nil
+		a.mu.Unlock()
+	}()
+
+	// Reset the backoff loop's notion of "connected" by resetting deadline
+	// handling to the caller; here we simply read until error.
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		var msgs []map[string]interface{}
+		if err := conn.ReadJSON(&msgs); err != nil {
+			return fmt.Errorf("read failed: %v", err)
+		}
+
+		for _, msg := range msgs {
+			update, ok := a.parseStreamMessage(msg)
+			if !ok {
+				continue
+			}
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// parseStreamMessage converts a raw Alpaca stream message (trade "t", quote
+// "q", or bar "b") into a StreamUpdate, computing Change/ChangePct against
+// the last price this provider saw for the ticker (the prior stream push,
+// not the day's open, since that's the only reference point available
+// tick-to-tick). The first push for a ticker in a connection's lifetime has
+// no prior price to diff against, so it reports a zero change.
+func (a *AlpacaProvider) parseStreamMessage(msg map[string]interface{}) (StreamUpdate, bool) {
+	msgType, _ := msg["T"].(string)
+	ticker, _ := msg["S"].(string)
+	if ticker == "" {
+		return StreamUpdate{}, false
+	}
+
+	var price float64
+	switch msgType {
+	case "t": // trade
+		price, _ = msg["p"].(float64)
+	case "q": // quote
+		bid, _ := msg["bp"].(float64)
+		ask, _ := msg["ap"].(float64)
+		price = (bid + ask) / 2
+	case "b": // bar
+		price, _ = msg["c"].(float64)
+	default:
+		return StreamUpdate{}, false
+	}
+
+	if price == 0 {
+		return StreamUpdate{}, false
+	}
+
+	var change float64
+	var changePct string
+	a.priceMu.Lock()
+	if prev, ok := a.lastPrice[ticker]; ok && prev != 0 {
+		change = price - prev
+		changePct = fmt.Sprintf("%.2f%%", change/prev*100)
+	}
+	a.lastPrice[ticker] = price
+	a.priceMu.Unlock()
+
+	return StreamUpdate{
+		Ticker:    ticker,
+		Price:     price,
+		Change:    change,
+		ChangePct: changePct,
+		Timestamp: time.Now(),
+	}, true
+}
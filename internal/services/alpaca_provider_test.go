@@ -0,0 +1,111 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestAlpacaParseStreamMessageComputesChangeFromPreviousPrice verifies
+// parseStreamMessage diffs each trade against the last price seen for that
+// ticker, rather than always reporting a zero change.
+func TestAlpacaParseStreamMessageComputesChangeFromPreviousPrice(t *testing.T) {
+	a := &AlpacaProvider{lastPrice: make(map[string]float64)}
+
+	first, ok := a.parseStreamMessage(map[string]interface{}{"T": "t", "S": "AAPL", "p": 150.0})
+	if !ok {
+		t.Fatal("expected the first trade message to parse")
+	}
+	if first.Change != 0 || first.ChangePct != "" {
+		t.Errorf("first push has no prior price to diff against, got Change=%v ChangePct=%q", first.Change, first.ChangePct)
+	}
+
+	second, ok := a.parseStreamMessage(map[string]interface{}{"T": "t", "S": "AAPL", "p": 153.0})
+	if !ok {
+		t.Fatal("expected the second trade message to parse")
+	}
+	if second.Change != 3.0 {
+		t.Errorf("Change = %v, want 3.0", second.Change)
+	}
+	if second.ChangePct != "2.00%" {
+		t.Errorf("ChangePct = %q, want 2.00%%", second.ChangePct)
+	}
+}
+
+// TestAlpacaUnsubscribeSendsExplicitUnsubscribeFrame verifies Unsubscribe
+// sends an explicit "unsubscribe" frame for the removed tickers before
+// re-subscribing to what remains, since Alpaca's subscribe protocol is
+// additive and never drops a ticker on its own.
+func TestAlpacaUnsubscribeSendsExplicitUnsubscribeFrame(t *testing.T) {
+	var mu sync.Mutex
+	var received []map[string]interface{}
+	upgrader := websocket.Upgrader{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("server upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			mu.Lock()
+			received = append(received, msg)
+			mu.Unlock()
+		}
+	}))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("client dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	a := &AlpacaProvider{
+		subscribed: map[string]bool{"AAPL": true, "MSFT": true},
+		streamConn: conn,
+	}
+
+	a.Unsubscribe([]string{"MSFT"})
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(received)
+		mu.Unlock()
+		if n >= 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) < 2 {
+		t.Fatalf("expected an unsubscribe frame followed by a subscribe frame, got %d message(s): %+v", len(received), received)
+	}
+
+	unsub := received[0]
+	if unsub["action"] != "unsubscribe" {
+		t.Errorf("first message action = %v, want %q", unsub["action"], "unsubscribe")
+	}
+	if trades, _ := unsub["trades"].([]interface{}); len(trades) != 1 || trades[0] != "MSFT" {
+		t.Errorf("unsubscribe trades = %v, want [MSFT]", unsub["trades"])
+	}
+
+	sub := received[1]
+	if sub["action"] != "subscribe" {
+		t.Errorf("second message action = %v, want %q", sub["action"], "subscribe")
+	}
+}
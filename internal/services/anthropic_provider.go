@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// anthropicMessagesURL is var'd out so tests can point it at an httptest
+// server.
+var anthropicMessagesURL = "https://api.anthropic.com/v1/messages"
+
+// anthropicRequest is the body of a POST to Anthropic's /v1/messages API.
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	System    string             `json:"system,omitempty"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMessage `json:"messages"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// anthropicResponse is the shape of a successful /v1/messages response.
+type anthropicResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+// AnthropicProvider is a Provider backed by Anthropic's Claude
+// /v1/messages API.
+type AnthropicProvider struct {
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+// NewAnthropicProvider builds an AnthropicProvider using apiKey, defaulting
+// to claude-3-haiku-20240307 unless ANTHROPIC_MODEL overrides it.
+func NewAnthropicProvider(apiKey string) *AnthropicProvider {
+	return &AnthropicProvider{
+		apiKey:     apiKey,
+		model:      envString("ANTHROPIC_MODEL", "claude-3-haiku-20240307"),
+		httpClient: &http.Client{Timeout: aiRequestTimeout},
+	}
+}
+
+func (p *AnthropicProvider) Name() string { return "anthropic" }
+
+// Complete asks Anthropic's /v1/messages endpoint to answer userPrompt.
+func (p *AnthropicProvider) Complete(ctx context.Context, systemPrompt, userPrompt string, maxTokens int) (string, error) {
+	reqBody := anthropicRequest{
+		Model:     p.model,
+		System:    systemPrompt,
+		MaxTokens: maxTokens,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: userPrompt},
+		},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("anthropic: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", anthropicMessagesURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("anthropic: failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("anthropic: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("anthropic: failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("anthropic: returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var anthropicResp anthropicResponse
+	if err := json.Unmarshal(body, &anthropicResp); err != nil {
+		return "", fmt.Errorf("anthropic: failed to parse response: %w", err)
+	}
+
+	if len(anthropicResp.Content) == 0 {
+		return "", fmt.Errorf("anthropic: response contained no content blocks")
+	}
+
+	return anthropicResp.Content[0].Text, nil
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"strings"
+	"sync"
+)
+
+// backtestThresholds holds, per uppercase ticker, the change-percent cutoff
+// that the backtesting engine found to perform best historically. Populated
+// by BacktestHandler after each run and consulted by
+// GetRuleBasedRecommendation's fallback instead of a flat stock.Change > 0
+// rule.
+var (
+	backtestThresholdsMu sync.RWMutex
+	backtestThresholds   = make(map[string]float64)
+)
+
+// SetBacktestThreshold records the best-performing change-percent threshold
+// for ticker, as found by the backtesting engine.
+func SetBacktestThreshold(ticker string, threshold float64) {
+	backtestThresholdsMu.Lock()
+	defer backtestThresholdsMu.Unlock()
+	backtestThresholds[strings.ToUpper(ticker)] = threshold
+}
+
+// BacktestThreshold returns the persisted best-performing threshold for
+// ticker and whether a backtest has been run for it.
+func BacktestThreshold(ticker string) (float64, bool) {
+	backtestThresholdsMu.RLock()
+	defer backtestThresholdsMu.RUnlock()
+	threshold, ok := backtestThresholds[strings.ToUpper(ticker)]
+	return threshold, ok
+}
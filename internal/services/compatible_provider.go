@@ -0,0 +1,91 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// CompatibleProvider is a Provider for any server speaking OpenAI's chat
+// completions wire format against a configurable base URL - Ollama, LM
+// Studio, Groq, or similar, for operators who want to run a purely local
+// model behind a corporate network.
+type CompatibleProvider struct {
+	name       string
+	baseURL    string
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+// NewCompatibleProvider builds a CompatibleProvider named name, sending
+// requests to baseURL+"/v1/chat/completions" with model and, if apiKey is
+// non-empty, a Bearer Authorization header.
+func NewCompatibleProvider(name, baseURL, apiKey, model string) *CompatibleProvider {
+	return &CompatibleProvider{
+		name:       name,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+		httpClient: &http.Client{Timeout: aiRequestTimeout},
+	}
+}
+
+func (p *CompatibleProvider) Name() string { return p.name }
+
+// Complete asks the configured endpoint to answer userPrompt using
+// OpenAI's chat completions request/response shapes.
+func (p *CompatibleProvider) Complete(ctx context.Context, systemPrompt, userPrompt string, maxTokens int) (string, error) {
+	reqBody := OpenAIRequest{
+		Model: p.model,
+		Messages: []Message{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+		MaxTokens: maxTokens,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to marshal request: %w", p.name, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/v1/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to build request: %w", p.name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s: request failed: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to read response: %w", p.name, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: returned status %d: %s", p.name, resp.StatusCode, string(body))
+	}
+
+	var completionResp OpenAIResponse
+	if err := json.Unmarshal(body, &completionResp); err != nil {
+		return "", fmt.Errorf("%s: failed to parse response: %w", p.name, err)
+	}
+
+	if len(completionResp.Choices) == 0 {
+		return "", fmt.Errorf("%s: response contained no choices", p.name)
+	}
+
+	return completionResp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/martinrizk/investify/internal/estimator"
+	"github.com/martinrizk/investify/internal/indicators"
+)
+
+// Factors is the cross-sectional factor panel FactorModelService computes
+// for a ticker at a point in time: momentum over a few lookbacks, a
+// short-term reversal rate, rolling volatility, size, and intraday skew.
+type Factors struct {
+	Momentum1d   float64
+	Momentum5d   float64
+	Momentum20d  float64
+	Reversal     float64
+	Volatility   float64
+	Size         float64
+	IntradaySkew float64
+}
+
+// vector returns Factors in the same order factorNames labels them, the
+// shape the regression is fit and queried with.
+func (f Factors) vector() []float64 {
+	return []float64{f.Momentum1d, f.Momentum5d, f.Momentum20d, f.Reversal, f.Volatility, f.Size, f.IntradaySkew}
+}
+
+// factorNames labels Factors.vector()'s elements, in the same order, for
+// turning fitted regression coefficients into human-readable strings.
+var factorNames = []string{
+	"1-day momentum", "5-day momentum", "20-day momentum",
+	"short-term reversal", "volatility", "size", "intraday skew",
+}
+
+// factorWindow is how many trailing snapshots momentum/reversal/volatility
+// look back over.
+const factorWindow = 20
+
+// maxFactorHistory bounds how many recent snapshots FactorModelService
+// keeps per ticker, regardless of factorWindow, so a ticker queried
+// indefinitely doesn't grow its history unbounded.
+const maxFactorHistory = 120
+
+// minFactorTrainingRows is the fewest (factors, next-period-return) pairs
+// Score needs before trusting a freshly fit regression.
+const minFactorTrainingRows = 10
+
+// FactorModelService computes the cross-sectional factor panel for a
+// ticker and fits a rolling multivariate linear regression of next-period
+// return on those factors, alongside TFModelService's price-level
+// predictions.
+type FactorModelService struct {
+	historyMu sync.Mutex
+	history   map[string][]*StockInfo
+}
+
+// NewFactorModelService creates a new factor model service.
+func NewFactorModelService() *FactorModelService {
+	return &FactorModelService{history: make(map[string][]*StockInfo)}
+}
+
+// recordSnapshot appends stock to its ticker's ring buffer and returns the
+// accumulated history so far, capped at maxFactorHistory.
+func (fm *FactorModelService) recordSnapshot(stock *StockInfo) []*StockInfo {
+	fm.historyMu.Lock()
+	defer fm.historyMu.Unlock()
+
+	snapshots := append(fm.history[stock.Ticker], stock)
+	if len(snapshots) > maxFactorHistory {
+		snapshots = snapshots[len(snapshots)-maxFactorHistory:]
+	}
+	fm.history[stock.Ticker] = snapshots
+	return snapshots
+}
+
+// ComputeFactors derives stock's factor panel from its own OHLC snapshot
+// and a chronological closes series ending at stock's own price.
+func ComputeFactors(stock *StockInfo, closes []float64) Factors {
+	var f Factors
+	f.Momentum1d = returnOverLookback(closes, 1)
+	f.Momentum5d = returnOverLookback(closes, 5)
+	f.Momentum20d = returnOverLookback(closes, factorWindow)
+	f.Reversal = negativeReturnRate(closes, factorWindow)
+	f.Volatility = stddevOfReturns(closes, factorWindow)
+	f.Size = logMarketCap(stock.MarketCap)
+
+	if dayRange := stock.High - stock.Low; dayRange != 0 {
+		f.IntradaySkew = (stock.Price - stock.Open) / dayRange
+	}
+
+	return f
+}
+
+// returnOverLookback returns the fractional return from lookback closes ago
+// to the latest close, or 0 if there isn't enough history.
+func returnOverLookback(closes []float64, lookback int) float64 {
+	if len(closes) < lookback+1 {
+		return 0
+	}
+	start := closes[len(closes)-lookback-1]
+	if start == 0 {
+		return 0
+	}
+	return (closes[len(closes)-1] - start) / start
+}
+
+// negativeReturnRate is the fraction of single-period returns over the
+// trailing window that were negative, the "IRR strategy" short-term
+// reversal signal.
+func negativeReturnRate(closes []float64, window int) float64 {
+	if len(closes) < 2 {
+		return 0
+	}
+	if window > len(closes)-1 {
+		window = len(closes) - 1
+	}
+
+	start := len(closes) - window
+	var negatives int
+	for i := start; i < len(closes); i++ {
+		if closes[i] < closes[i-1] {
+			negatives++
+		}
+	}
+	return float64(negatives) / float64(window)
+}
+
+// stddevOfReturns is the population standard deviation of single-period
+// returns over the trailing window.
+func stddevOfReturns(closes []float64, window int) float64 {
+	if len(closes) < 2 {
+		return 0
+	}
+	if window > len(closes)-1 {
+		window = len(closes) - 1
+	}
+
+	start := len(closes) - window
+	returns := make([]float64, 0, window)
+	for i := start; i < len(closes); i++ {
+		if closes[i-1] == 0 {
+			continue
+		}
+		returns = append(returns, (closes[i]-closes[i-1])/closes[i-1])
+	}
+	return indicators.StdDev(returns)
+}
+
+// logMarketCap parses a formatted market cap string (e.g. "$2.5T") and
+// returns its natural log, or 0 if it can't be parsed.
+func logMarketCap(marketCap string) float64 {
+	value := parseMarketCapValue(marketCap)
+	if value <= 0 {
+		return 0
+	}
+	return math.Log(value)
+}
+
+// FactorScore is a continuous recommendation score produced by regressing
+// next-period returns on Factors, along with the fitted weight assigned to
+// each factor so callers can explain the score.
+type FactorScore struct {
+	// Score is the regression's predicted next-period return, expressed as
+	// a percent (matching the convention ChangePct elsewhere uses).
+	Score float64
+
+	Coefficients map[string]float64
+}
+
+// Score fits a rolling OLS regression of next-period return on the factor
+// panel across stock's ticker's recorded history, then scores stock's
+// current factors. Returns an error if there isn't enough history yet to
+// fit a regression.
+func (fm *FactorModelService) Score(stock *StockInfo) (*FactorScore, error) {
+	snapshots := fm.recordSnapshot(stock)
+
+	closes := make([]float64, len(snapshots))
+	for i, s := range snapshots {
+		closes[i] = s.Price
+	}
+
+	var features [][]float64
+	var targets []float64
+	for i := factorWindow; i < len(snapshots)-1; i++ {
+		if closes[i] == 0 {
+			continue
+		}
+		factors := ComputeFactors(snapshots[i], closes[:i+1])
+		features = append(features, factors.vector())
+		targets = append(targets, (closes[i+1]-closes[i])/closes[i])
+	}
+
+	if len(features) < minFactorTrainingRows {
+		return nil, fmt.Errorf("factor model: need at least %d historical snapshots for %s, have %d", minFactorTrainingRows+factorWindow+1, stock.Ticker, len(snapshots))
+	}
+
+	model := &estimator.OLSEstimator{}
+	if err := model.Fit(features, targets); err != nil {
+		return nil, fmt.Errorf("factor model: fit failed: %w", err)
+	}
+
+	currentFactors := ComputeFactors(stock, closes)
+	predictedReturn, _, err := model.Predict(currentFactors.vector())
+	if err != nil {
+		return nil, fmt.Errorf("factor model: predict failed: %w", err)
+	}
+
+	coefficients := model.Coefficients()
+	named := make(map[string]float64, len(factorNames))
+	for i, name := range factorNames {
+		if i+1 < len(coefficients) {
+			named[name] = coefficients[i+1]
+		}
+	}
+
+	return &FactorScore{
+		Score:        predictedReturn * 100,
+		Coefficients: named,
+	}, nil
+}
+
+// Recommendation thresholds for FactorScore.Score, a predicted
+// next-period percent return.
+const (
+	strongBuyThreshold  = 2.0
+	buyThreshold        = 0.5
+	sellThreshold       = -0.5
+	strongSellThreshold = -2.0
+)
+
+// Recommendation maps Score to STRONG BUY / BUY / HOLD / SELL / STRONG
+// SELL.
+func (s *FactorScore) Recommendation() string {
+	switch {
+	case s.Score >= strongBuyThreshold:
+		return "STRONG BUY"
+	case s.Score >= buyThreshold:
+		return "BUY"
+	case s.Score <= strongSellThreshold:
+		return "STRONG SELL"
+	case s.Score <= sellThreshold:
+		return "SELL"
+	default:
+		return "HOLD"
+	}
+}
+
+// FactorExplanations turns the fitted per-factor coefficients into
+// human-readable strings ("20-day momentum: +0.1832"), sorted by influence
+// so the most important factors lead, suitable for StockPrediction.Factors.
+func (s *FactorScore) FactorExplanations() []string {
+	type weighted struct {
+		name  string
+		coeff float64
+	}
+	weights := make([]weighted, 0, len(s.Coefficients))
+	for name, coeff := range s.Coefficients {
+		weights = append(weights, weighted{name, coeff})
+	}
+	sort.Slice(weights, func(i, j int) bool {
+		return math.Abs(weights[i].coeff) > math.Abs(weights[j].coeff)
+	})
+
+	explanations := make([]string, 0, len(weights))
+	for _, w := range weights {
+		explanations = append(explanations, fmt.Sprintf("%s: %+.4f", w.name, w.coeff))
+	}
+	return explanations
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// FinnhubStreamer maintains a websocket connection to Finnhub's trade feed
+// and pushes live ticks into StockInfo updates, refreshing the shared quote
+// cache along the way so REST calls for the same ticker see fresh data
+// without waiting on cacheTTL to expire.
+type FinnhubStreamer struct {
+	apiKey string
+
+	mu         sync.Mutex
+	subscribed map[string]bool
+	conn       *websocket.Conn
+}
+
+// NewFinnhubStreamer reads FINNHUB_API_KEY from the environment. Finnhub's
+// websocket feed requires a real (even free-tier) key; the "demo" token used
+// by fetchFromFinnhub's REST quote doesn't work here.
+func NewFinnhubStreamer() (*FinnhubStreamer, error) {
+	apiKey := os.Getenv("FINNHUB_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("FINNHUB_API_KEY not set")
+	}
+	return &FinnhubStreamer{
+		apiKey:     apiKey,
+		subscribed: make(map[string]bool),
+	}, nil
+}
+
+// StreamQuotes subscribes to tickers on Finnhub's trade feed and returns a
+// channel of StockInfo updates, one per trade tick. The channel is closed
+// when ctx is cancelled.
+func (f *FinnhubStreamer) StreamQuotes(ctx context.Context, tickers []string) (<-chan *StockInfo, error) {
+	updates := make(chan *StockInfo, 64)
+
+	f.mu.Lock()
+	for _, t := range tickers {
+		f.subscribed[strings.ToUpper(t)] = true
+	}
+	f.mu.Unlock()
+
+	go f.runStream(ctx, updates)
+
+	return updates, nil
+}
+
+// Subscribe adds tickers to the active stream's subscription set.
+func (f *FinnhubStreamer) Subscribe(tickers []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range tickers {
+		f.subscribed[strings.ToUpper(t)] = true
+	}
+	f.resubscribe()
+}
+
+// resubscribe sends a subscribe message for every ticker in the current set.
+// Callers must hold f.mu.
+func (f *FinnhubStreamer) resubscribe() {
+	if f.conn == nil {
+		return
+	}
+	for t := range f.subscribed {
+		if err := f.conn.WriteJSON(map[string]string{"type": "subscribe", "symbol": t}); err != nil {
+			log.Printf("finnhub: failed to subscribe to %s: %v", t, err)
+		}
+	}
+}
+
+// finnhubStreamHeartbeat bounds how long the stream waits for a message
+// (trade or Finnhub's own ping) before treating the connection as dead.
+const finnhubStreamHeartbeat = 60 * time.Second
+
+// runStream maintains the websocket connection, reconnecting with
+// exponential backoff (capped at 30s) until ctx is cancelled.
+func (f *FinnhubStreamer) runStream(ctx context.Context, updates chan<- *StockInfo) {
+	defer close(updates)
+
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if err := f.connectAndConsume(ctx, updates); err != nil {
+			log.Printf("finnhub: stream error, reconnecting in %v: %v", backoff, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff + time.Duration(rand.Intn(250))*time.Millisecond):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// connectAndConsume dials the stream, subscribes, and reads trade messages
+// until the connection drops, the heartbeat times out, or ctx is cancelled.
+func (f *FinnhubStreamer) connectAndConsume(ctx context.Context, updates chan<- *StockInfo) error {
+	url := fmt.Sprintf("wss://ws.finnhub.io/?token=%s", f.apiKey)
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return fmt.Errorf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	f.mu.Lock()
+	f.conn = conn
+	f.resubscribe()
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.conn = nil
+		f.mu.Unlock()
+	}()
+
+	conn.SetReadDeadline(time.Now().Add(finnhubStreamHeartbeat))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		var msg finnhubStreamMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return fmt.Errorf("read failed: %v", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(finnhubStreamHeartbeat))
+
+		if msg.Type != "trade" {
+			continue
+		}
+
+		for _, tick := range msg.Data {
+			info := finnhubTickToStockInfo(tick)
+			cacheSet(info.Ticker, info)
+
+			select {
+			case updates <- info:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// finnhubStreamMessage is a single frame from Finnhub's trade websocket:
+// {"type":"trade","data":[{"s":"AAPL","p":189.3,"v":100,"t":1690000000000}]}
+type finnhubStreamMessage struct {
+	Type string        `json:"type"`
+	Data []finnhubTick `json:"data"`
+}
+
+type finnhubTick struct {
+	Symbol    string  `json:"s"`
+	Price     float64 `json:"p"`
+	Volume    float64 `json:"v"`
+	Timestamp int64   `json:"t"`
+}
+
+// finnhubTickToStockInfo builds a StockInfo update from a trade tick,
+// computing Change/ChangePct against whatever price is currently cached for
+// the ticker.
+func finnhubTickToStockInfo(tick finnhubTick) *StockInfo {
+	ticker := strings.ToUpper(tick.Symbol)
+
+	change := 0.0
+	changePct := "0.00%"
+	if prev, ok := cacheGet(ticker); ok && prev.Price > 0 {
+		change = tick.Price - prev.Price
+		changePct = fmt.Sprintf("%.2f%%", (change/prev.Price)*100)
+	}
+
+	return &StockInfo{
+		Ticker:    ticker,
+		Price:     tick.Price,
+		Change:    change,
+		ChangePct: changePct,
+		DataAge:   0,
+	}
+}
+
+// StreamQuotes opens a live trade feed for tickers via the shared Finnhub
+// streamer, returning an error if FINNHUB_API_KEY isn't configured.
+func StreamQuotes(ctx context.Context, tickers []string) (<-chan *StockInfo, error) {
+	if finnhubStreamer == nil {
+		return nil, fmt.Errorf("live quote streaming not configured: set FINNHUB_API_KEY")
+	}
+	return finnhubStreamer.StreamQuotes(ctx, tickers)
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"encoding/csv"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/martinrizk/investify/internal/ratelimit"
+)
+
+// historyCacheDir is where HistoryStore persists each ticker's bars as CSV
+// (no parquet library is available to vendor in this environment, so this
+// substitutes the same row/column OHLCV layout in a format the standard
+// library can read and write without a new dependency), so a restart
+// doesn't require re-fetching before indicators can warm up. Configurable
+// via HISTORY_CACHE_DIR.
+var historyCacheDir = envString("HISTORY_CACHE_DIR", "data/history_cache")
+
+// historyTTL controls how long a cached series is served as fresh before
+// GetHistory kicks off a background refresh; daily bars only need
+// refreshing once a session, so the default is long. Configurable via
+// HISTORY_TTL_MINUTES.
+var historyTTL = time.Duration(envInt("HISTORY_TTL_MINUTES", 360)) * time.Minute
+
+// historyFetchRatePerMin/historyFetchBurst bound how often HistoryStore
+// calls through to FetchHistorical, so a burst of cold tickers (e.g. the
+// startup watchlist warm-up) can't overwhelm a rate-limited upstream
+// provider. Configurable via HISTORY_FETCH_RATE_PER_MIN/HISTORY_FETCH_BURST.
+var (
+	historyFetchRatePerMin = envInt("HISTORY_FETCH_RATE_PER_MIN", 30)
+	historyFetchBurst      = envInt("HISTORY_FETCH_BURST", 5)
+)
+
+// historyWatchlist is the comma-separated ticker list NewHistoryStore warms
+// on startup, overridable via PREDICTION_WATCHLIST.
+var historyWatchlist = envString("PREDICTION_WATCHLIST", "AAPL,MSFT,GOOGL,AMZN,TSLA")
+
+// historyEntry is one ticker's cached series plus when it was fetched, used
+// to decide whether GetHistory serves it as-is or triggers a background
+// refresh.
+type historyEntry struct {
+	bars      []Bar
+	fetchedAt time.Time
+}
+
+// HistoryStore caches FetchHistorical's per-ticker OHLCV series in memory
+// and on disk, serving stale data immediately past historyTTL while
+// refreshing it in the background (stale-while-revalidate) instead of
+// blocking callers on the hot prediction path, and rate limiting how often
+// it calls through to the upstream provider via a token bucket.
+type HistoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*historyEntry
+
+	refreshingMu sync.Mutex
+	refreshing   map[string]bool
+
+	limiter *ratelimit.TokenBucket
+}
+
+// NewHistoryStore creates a HistoryStore, ensures its disk cache directory
+// exists, and warms historyWatchlist in the background.
+func NewHistoryStore() *HistoryStore {
+	if err := os.MkdirAll(historyCacheDir, 0o755); err != nil {
+		log.Printf("history store: could not create cache dir %s: %v", historyCacheDir, err)
+	}
+
+	hs := &HistoryStore{
+		entries:    make(map[string]*historyEntry),
+		refreshing: make(map[string]bool),
+		limiter:    ratelimit.New(historyFetchRatePerMin, historyFetchBurst),
+	}
+
+	go hs.warmWatchlist()
+
+	return hs
+}
+
+// warmWatchlist fetches and caches history for each ticker in
+// historyWatchlist, so the first prediction for a watchlisted ticker isn't
+// cold.
+func (hs *HistoryStore) warmWatchlist() {
+	for _, ticker := range strings.Split(historyWatchlist, ",") {
+		ticker = strings.ToUpper(strings.TrimSpace(ticker))
+		if ticker == "" {
+			continue
+		}
+		if _, err := hs.fetchAndStore(ticker, "1Day"); err != nil {
+			log.Printf("history store: warm-up failed for %s: %v", ticker, err)
+		}
+	}
+}
+
+// GetHistory returns up to n most recent bars for ticker at the given
+// interval, preferring an unexpired in-memory or disk-cached entry. Past
+// historyTTL it still returns the stale series immediately but kicks off a
+// background refresh, so hot-path callers (predictions) are never blocked
+// on the network.
+func (hs *HistoryStore) GetHistory(ticker, interval string, n int) ([]Bar, error) {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+
+	hs.mu.Lock()
+	entry, ok := hs.entries[ticker]
+	hs.mu.Unlock()
+
+	if !ok {
+		if diskBars, err := hs.loadFromDisk(ticker); err == nil && len(diskBars) > 0 {
+			entry = &historyEntry{bars: diskBars}
+			hs.mu.Lock()
+			hs.entries[ticker] = entry
+			hs.mu.Unlock()
+			ok = true
+		}
+	}
+
+	if !ok {
+		bars, err := hs.fetchAndStore(ticker, interval)
+		if err != nil {
+			return nil, err
+		}
+		return lastNBars(bars, n), nil
+	}
+
+	if time.Since(entry.fetchedAt) > historyTTL {
+		hs.refreshInBackground(ticker, interval)
+	}
+
+	return lastNBars(entry.bars, n), nil
+}
+
+// lastNBars returns bars' final n entries, or all of bars if n is
+// non-positive or there aren't that many.
+func lastNBars(bars []Bar, n int) []Bar {
+	if n <= 0 || n >= len(bars) {
+		return bars
+	}
+	return bars[len(bars)-n:]
+}
+
+// refreshInBackground kicks off at most one in-flight refetch per ticker,
+// so concurrent stale reads don't each trigger their own upstream call.
+func (hs *HistoryStore) refreshInBackground(ticker, interval string) {
+	hs.refreshingMu.Lock()
+	if hs.refreshing[ticker] {
+		hs.refreshingMu.Unlock()
+		return
+	}
+	hs.refreshing[ticker] = true
+	hs.refreshingMu.Unlock()
+
+	go func() {
+		defer func() {
+			hs.refreshingMu.Lock()
+			delete(hs.refreshing, ticker)
+			hs.refreshingMu.Unlock()
+		}()
+		if _, err := hs.fetchAndStore(ticker, interval); err != nil {
+			log.Printf("history store: background refresh failed for %s: %v", ticker, err)
+		}
+	}()
+}
+
+// fetchAndStore waits for a rate-limit token, then fetches ticker's series
+// from FetchHistorical and persists it to memory and disk.
+func (hs *HistoryStore) fetchAndStore(ticker, interval string) ([]Bar, error) {
+	hs.limiter.Wait()
+
+	bars, err := FetchHistorical(ticker, interval, "1y")
+	if err != nil {
+		return nil, err
+	}
+
+	hs.mu.Lock()
+	hs.entries[ticker] = &historyEntry{bars: bars, fetchedAt: time.Now()}
+	hs.mu.Unlock()
+
+	if err := hs.saveToDisk(ticker, bars); err != nil {
+		log.Printf("history store: could not persist %s to disk: %v", ticker, err)
+	}
+
+	return bars, nil
+}
+
+// saveToDisk writes bars for ticker as CSV.
+func (hs *HistoryStore) saveToDisk(ticker string, bars []Bar) error {
+	f, err := os.Create(filepath.Join(historyCacheDir, ticker+".csv"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	for _, bar := range bars {
+		row := []string{
+			strconv.FormatInt(bar.Timestamp.Unix(), 10),
+			strconv.FormatFloat(bar.Open, 'f', -1, 64),
+			strconv.FormatFloat(bar.High, 'f', -1, 64),
+			strconv.FormatFloat(bar.Low, 'f', -1, 64),
+			strconv.FormatFloat(bar.Close, 'f', -1, 64),
+			strconv.FormatInt(bar.Volume, 10),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return w.Error()
+}
+
+// loadFromDisk reads back a ticker's CSV cache written by saveToDisk.
+func (hs *HistoryStore) loadFromDisk(ticker string) ([]Bar, error) {
+	f, err := os.Open(filepath.Join(historyCacheDir, ticker+".csv"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	bars := make([]Bar, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != 6 {
+			continue
+		}
+		unixSeconds, _ := strconv.ParseInt(row[0], 10, 64)
+		open, _ := strconv.ParseFloat(row[1], 64)
+		high, _ := strconv.ParseFloat(row[2], 64)
+		low, _ := strconv.ParseFloat(row[3], 64)
+		closePrice, _ := strconv.ParseFloat(row[4], 64)
+		volume, _ := strconv.ParseInt(row[5], 10, 64)
+		bars = append(bars, Bar{
+			Timestamp: time.Unix(unixSeconds, 0),
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     closePrice,
+			Volume:    volume,
+		})
+	}
+	return bars, nil
+}
+
+// historyStore is the shared HistoryStore backing GetHistory, warmed on
+// package init like quoteCache.
+var historyStore = NewHistoryStore()
+
+// GetHistory returns up to n most recent cached bars for ticker at the
+// given interval, fetching and caching them first if this is the first
+// request for ticker. See HistoryStore for the caching/staleness policy.
+func GetHistory(ticker, interval string, n int) ([]Bar, error) {
+	return historyStore.GetHistory(ticker, interval, n)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// marketOverviewSymbols is the single batched Yahoo Finance request
+// GetMarketOverview issues, covering every index/commodity/currency bucket
+// in MarketOverview.
+const marketOverviewSymbols = "^DJI,^IXIC,^GSPC,^N225,^HSI,^FTSE,^GDAXI,CL=F,GC=F,JPY=X,EUR=X,^TNX"
+
+// IndexQuote is one line item in a MarketOverview bucket.
+type IndexQuote struct {
+	Name      string  `json:"name"`
+	Symbol    string  `json:"symbol"`
+	Price     float64 `json:"price"`
+	Change    float64 `json:"change"`
+	ChangePct string  `json:"change_pct"`
+}
+
+// MarketOverview is a snapshot of major global indices, commodities,
+// currencies, and the 10-year Treasury yield, for a dashboard view beyond a
+// single ticker lookup.
+type MarketOverview struct {
+	USIndices            map[string]IndexQuote `json:"us_indices"`
+	InternationalIndices map[string]IndexQuote `json:"international_indices"`
+	Commodities          map[string]IndexQuote `json:"commodities"`
+	Currencies           map[string]IndexQuote `json:"currencies"`
+	TreasuryYield        IndexQuote            `json:"treasury_yield"`
+	IsClosed             bool                  `json:"is_closed"`
+	LastUpdated          string                `json:"last_updated"`
+}
+
+// marketSymbolMeta describes where a Yahoo symbol belongs in MarketOverview
+// and the display name for it.
+type marketSymbolMeta struct {
+	name   string
+	bucket string // "us", "intl", "commodity", "currency", "treasury"
+	key    string // map key within its bucket
+}
+
+// marketSymbolIndex maps each symbol in marketOverviewSymbols to its
+// MarketOverview placement.
+var marketSymbolIndex = map[string]marketSymbolMeta{
+	"^DJI":   {name: "Dow Jones Industrial Average", bucket: "us", key: "dow"},
+	"^IXIC":  {name: "NASDAQ Composite", bucket: "us", key: "nasdaq"},
+	"^GSPC":  {name: "S&P 500", bucket: "us", key: "sp500"},
+	"^N225":  {name: "Nikkei 225", bucket: "intl", key: "tokyo"},
+	"^HSI":   {name: "Hang Seng Index", bucket: "intl", key: "hong_kong"},
+	"^FTSE":  {name: "FTSE 100", bucket: "intl", key: "london"},
+	"^GDAXI": {name: "DAX", bucket: "intl", key: "frankfurt"},
+	"CL=F":   {name: "Crude Oil", bucket: "commodity", key: "oil"},
+	"GC=F":   {name: "Gold", bucket: "commodity", key: "gold"},
+	"JPY=X":  {name: "USD/JPY", bucket: "currency", key: "yen"},
+	"EUR=X":  {name: "USD/EUR", bucket: "currency", key: "euro"},
+	"^TNX":   {name: "10-Year Treasury Yield", bucket: "treasury", key: "treasury_10y"},
+}
+
+// GetMarketOverview fetches every index/commodity/currency in
+// marketOverviewSymbols with a single batched Yahoo Finance request and
+// sorts the results into MarketOverview's buckets.
+func GetMarketOverview() (*MarketOverview, error) {
+	resp, err := fetchYahooQuotes(marketOverviewSymbols)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch market overview: %v", err)
+	}
+
+	overview := &MarketOverview{
+		USIndices:            make(map[string]IndexQuote),
+		InternationalIndices: make(map[string]IndexQuote),
+		Commodities:          make(map[string]IndexQuote),
+		Currencies:           make(map[string]IndexQuote),
+		IsClosed:             !isUSMarketOpen(time.Now()),
+		LastUpdated:          time.Now().Format(time.RFC3339),
+	}
+
+	for _, q := range resp.QuoteResponse.Result {
+		meta, ok := marketSymbolIndex[q.Symbol]
+		if !ok {
+			continue
+		}
+
+		quote := IndexQuote{
+			Name:      meta.name,
+			Symbol:    q.Symbol,
+			Price:     q.RegularMarketPrice,
+			Change:    q.RegularMarketChange,
+			ChangePct: fmt.Sprintf("%.2f%%", q.RegularMarketChangePercent),
+		}
+
+		switch meta.bucket {
+		case "us":
+			overview.USIndices[meta.key] = quote
+		case "intl":
+			overview.InternationalIndices[meta.key] = quote
+		case "commodity":
+			overview.Commodities[meta.key] = quote
+		case "currency":
+			overview.Currencies[meta.key] = quote
+		case "treasury":
+			overview.TreasuryYield = quote
+		}
+	}
+
+	return overview, nil
+}
+
+// usEastern is loaded once at startup; if the tzdata needed to resolve it
+// isn't available in this environment, isUSMarketOpen falls back to
+// treating the market as open rather than guessing wrong based on UTC.
+var usEastern, usEasternErr = time.LoadLocation("America/New_York")
+
+// isUSMarketOpen reports whether t falls within regular US market hours
+// (9:30-16:00 ET, Monday-Friday). It does not account for market holidays.
+func isUSMarketOpen(t time.Time) bool {
+	if usEasternErr != nil {
+		log.Printf("market overview: could not load America/New_York location, assuming market open: %v", usEasternErr)
+		return true
+	}
+
+	local := t.In(usEastern)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+
+	open := time.Date(local.Year(), local.Month(), local.Day(), 9, 30, 0, 0, usEastern)
+	marketClose := time.Date(local.Year(), local.Month(), local.Day(), 16, 0, 0, 0, usEastern)
+	return !local.Before(open) && local.Before(marketClose)
+}
@@ -0,0 +1,49 @@
+package services
+
+import "sync/atomic"
+
+// wsMetrics holds cumulative Prometheus-style counters for the event-driven
+// WebSocket broadcast pipeline (internal/handlers), so ticks processed and
+// fan-out volume are observable on /api/metrics without needing a real
+// Prometheus client library.
+var wsMetrics struct {
+	ticksReceived        int64
+	technicalsRecomputed int64
+	wsMessagesSent       int64
+	slowClientDrops      int64
+}
+
+// WSMetrics is a snapshot of the broadcast pipeline's cumulative counters.
+type WSMetrics struct {
+	TicksReceived        int64 `json:"ticks_received"`
+	TechnicalsRecomputed int64 `json:"technicals_recomputed"`
+	WSMessagesSent       int64 `json:"ws_messages_sent"`
+	SlowClientDrops      int64 `json:"slow_client_drops"`
+}
+
+// IncTicksReceived counts one raw tick consumed off a ticker's stream, before
+// debouncing.
+func IncTicksReceived() { atomic.AddInt64(&wsMetrics.ticksReceived, 1) }
+
+// IncTechnicalsRecomputed counts one actual recomputation of technical
+// indicators, as opposed to a cache hit because the bar window hasn't
+// advanced.
+func IncTechnicalsRecomputed() { atomic.AddInt64(&wsMetrics.technicalsRecomputed, 1) }
+
+// IncWSMessagesSent counts one message successfully enqueued to a client's
+// send buffer.
+func IncWSMessagesSent() { atomic.AddInt64(&wsMetrics.wsMessagesSent, 1) }
+
+// IncSlowClientDrops counts one message dropped because a client's send
+// buffer was full.
+func IncSlowClientDrops() { atomic.AddInt64(&wsMetrics.slowClientDrops, 1) }
+
+// WSMetricsSnapshot returns the broadcast pipeline's cumulative counters.
+func WSMetricsSnapshot() WSMetrics {
+	return WSMetrics{
+		TicksReceived:        atomic.LoadInt64(&wsMetrics.ticksReceived),
+		TechnicalsRecomputed: atomic.LoadInt64(&wsMetrics.technicalsRecomputed),
+		WSMessagesSent:       atomic.LoadInt64(&wsMetrics.wsMessagesSent),
+		SlowClientDrops:      atomic.LoadInt64(&wsMetrics.slowClientDrops),
+	}
+}
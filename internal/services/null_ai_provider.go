@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// NullProvider is the rule-based engine wrapped as a Provider, so it can
+// sit at the end of an AIService's fallback chain like any other backend
+// and guarantee the chain always produces an answer. Complete never
+// errors; AIService.GetStockRecommendation special-cases NullProvider to
+// call the richer, structured-StockInfo rule-based path instead, since
+// Complete's prompt/response shape can't carry stock.High/Low/Change the
+// way the real rule engine needs.
+type NullProvider struct{}
+
+func (NullProvider) Name() string { return "rule-based" }
+
+func (NullProvider) Complete(ctx context.Context, systemPrompt, userPrompt string, maxTokens int) (string, error) {
+	return "HOLD - Insufficient structured data for rule-based analysis via the generic Provider interface", nil
+}
+
+// aiProviderOrderEnv lists the provider names (in the order AIService
+// should try them) AI_PROVIDER_ORDER selects from: "openai", "anthropic",
+// "compatible", and "rule-based". Unset or invalid names fall back to
+// defaultAIProviderOrder.
+const aiProviderOrderEnv = "AI_PROVIDER_ORDER"
+
+var defaultAIProviderOrder = []string{"openai", "anthropic", "compatible", "rule-based"}
+
+// buildDefaultAIProviders assembles the ordered Provider chain AIService
+// runs in production: AI_PROVIDER_ORDER (or defaultAIProviderOrder, if
+// unset) picks which backends to try and in what order, skipping any that
+// aren't configured (e.g. "anthropic" without ANTHROPIC_API_KEY).
+// NullProvider is always appended last, even if "rule-based" wasn't named,
+// so there's always an answer.
+func buildDefaultAIProviders() []Provider {
+	order := defaultAIProviderOrder
+	if raw := os.Getenv(aiProviderOrderEnv); raw != "" {
+		order = nil
+		for _, name := range strings.Split(raw, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				order = append(order, name)
+			}
+		}
+	}
+
+	providers := make([]Provider, 0, len(order)+1)
+	for _, name := range order {
+		if p := namedAIProvider(name); p != nil {
+			providers = append(providers, p)
+		}
+	}
+	providers = append(providers, NullProvider{})
+
+	return providers
+}
+
+// namedAIProvider builds the Provider for name from its environment
+// configuration, or returns nil if it isn't configured (or name is
+// unrecognized).
+func namedAIProvider(name string) Provider {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "openai":
+		if key := os.Getenv("OPENAI_API_KEY"); key != "" {
+			return NewOpenAIProvider(key)
+		}
+	case "anthropic", "claude":
+		if key := os.Getenv("ANTHROPIC_API_KEY"); key != "" {
+			return NewAnthropicProvider(key)
+		}
+	case "compatible", "ollama", "lmstudio", "groq":
+		if baseURL := os.Getenv("COMPATIBLE_BASE_URL"); baseURL != "" {
+			model := envString("COMPATIBLE_MODEL", "local-model")
+			apiKey := os.Getenv("COMPATIBLE_API_KEY")
+			return NewCompatibleProvider("compatible", baseURL, apiKey, model)
+		}
+	case "rule-based", "null", "none":
+		return NullProvider{}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"os"
+	"testing"
+)
+
+// TestBuildDefaultAIProvidersAlwaysEndsInNullProvider verifies
+// buildDefaultAIProviders appends NullProvider last even when
+// AI_PROVIDER_ORDER omits "rule-based" and no LLM backend is configured.
+func TestBuildDefaultAIProvidersAlwaysEndsInNullProvider(t *testing.T) {
+	t.Cleanup(func() {
+		os.Unsetenv(aiProviderOrderEnv)
+		os.Unsetenv("OPENAI_API_KEY")
+		os.Unsetenv("ANTHROPIC_API_KEY")
+		os.Unsetenv("COMPATIBLE_BASE_URL")
+	})
+	os.Setenv(aiProviderOrderEnv, "openai,anthropic")
+	os.Unsetenv("OPENAI_API_KEY")
+	os.Unsetenv("ANTHROPIC_API_KEY")
+	os.Unsetenv("COMPATIBLE_BASE_URL")
+
+	providers := buildDefaultAIProviders()
+	if len(providers) != 1 {
+		t.Fatalf("expected only NullProvider when no backend is configured, got %d providers", len(providers))
+	}
+	if providers[len(providers)-1].Name() != "rule-based" {
+		t.Errorf("expected NullProvider last, got %q", providers[len(providers)-1].Name())
+	}
+}
+
+// TestBuildDefaultAIProvidersHonorsOrder verifies AI_PROVIDER_ORDER
+// controls which configured backends are tried and in what order.
+func TestBuildDefaultAIProvidersHonorsOrder(t *testing.T) {
+	t.Cleanup(func() {
+		os.Unsetenv(aiProviderOrderEnv)
+		os.Unsetenv("OPENAI_API_KEY")
+		os.Unsetenv("ANTHROPIC_API_KEY")
+	})
+	os.Setenv(aiProviderOrderEnv, "anthropic,openai")
+	os.Setenv("OPENAI_API_KEY", "fake-openai-key")
+	os.Setenv("ANTHROPIC_API_KEY", "fake-anthropic-key")
+
+	providers := buildDefaultAIProviders()
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, p.Name())
+	}
+
+	want := []string{"anthropic", "openai", "rule-based"}
+	if len(names) != len(want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("expected provider order %v, got %v", want, names)
+			break
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// openAIChatCompletionsURL is var'd out so tests can point it at an
+// httptest server.
+var openAIChatCompletionsURL = "https://api.openai.com/v1/chat/completions"
+
+// OpenAIRequest represents the request to OpenAI's chat completions API.
+type OpenAIRequest struct {
+	Model          string                `json:"model"`
+	Messages       []Message             `json:"messages"`
+	MaxTokens      int                   `json:"max_tokens"`
+	ResponseFormat *OpenAIResponseFormat `json:"response_format,omitempty"`
+}
+
+// OpenAIResponseFormat asks the API to constrain its output to valid JSON;
+// set by CompleteJSON, left nil by Complete's free-form text requests.
+type OpenAIResponseFormat struct {
+	Type string `json:"type"`
+}
+
+// Message is one chat turn in an OpenAIRequest.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// OpenAIResponse represents the response from OpenAI's chat completions
+// API.
+type OpenAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+// OpenAIProvider is a Provider backed by OpenAI's chat completions API.
+type OpenAIProvider struct {
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+// NewOpenAIProvider builds an OpenAIProvider using apiKey, defaulting to
+// gpt-3.5-turbo unless OPENAI_MODEL overrides it.
+func NewOpenAIProvider(apiKey string) *OpenAIProvider {
+	return &OpenAIProvider{
+		apiKey:     apiKey,
+		model:      envString("OPENAI_MODEL", "gpt-3.5-turbo"),
+		httpClient: &http.Client{Timeout: aiRequestTimeout},
+	}
+}
+
+func (p *OpenAIProvider) Name() string { return "openai" }
+
+// Complete asks OpenAI's chat completions endpoint to answer userPrompt.
+func (p *OpenAIProvider) Complete(ctx context.Context, systemPrompt, userPrompt string, maxTokens int) (string, error) {
+	return p.complete(ctx, systemPrompt, userPrompt, maxTokens, false)
+}
+
+// CompleteJSON is like Complete but sets response_format to json_object, so
+// the API enforces valid JSON output rather than relying solely on the
+// prompt asking for it. Used by GetStructuredRecommendation.
+func (p *OpenAIProvider) CompleteJSON(ctx context.Context, systemPrompt, userPrompt string, maxTokens int) (string, error) {
+	return p.complete(ctx, systemPrompt, userPrompt, maxTokens, true)
+}
+
+func (p *OpenAIProvider) complete(ctx context.Context, systemPrompt, userPrompt string, maxTokens int, jsonMode bool) (string, error) {
+	reqBody := OpenAIRequest{
+		Model: p.model,
+		Messages: []Message{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+		MaxTokens: maxTokens,
+	}
+	if jsonMode {
+		reqBody.ResponseFormat = &OpenAIResponseFormat{Type: "json_object"}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("openai: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", openAIChatCompletionsURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("openai: failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("openai: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("openai: failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("openai: returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var openAIResp OpenAIResponse
+	if err := json.Unmarshal(body, &openAIResp); err != nil {
+		return "", fmt.Errorf("openai: failed to parse response: %w", err)
+	}
+
+	if len(openAIResp.Choices) == 0 {
+		return "", fmt.Errorf("openai: response contained no choices")
+	}
+
+	return openAIResp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// AlertStatus is the lifecycle a registered alert moves through: Watching
+// until the take-profit trigger first arms the trailing stop, then Armed
+// until either the trailing stop or the initial fixed stop fires.
+type AlertStatus string
+
+const (
+	AlertWatching  AlertStatus = "watching"
+	AlertArmed     AlertStatus = "armed"
+	AlertTriggered AlertStatus = "triggered"
+)
+
+// AlertState is one ticker's registered alert and the high-water mark the
+// monitor has observed for it, snapshotted for both the GET /alerts
+// endpoint and on-disk persistence.
+type AlertState struct {
+	Ticker         string      `json:"ticker"`
+	EntryPrice     float64     `json:"entry_price"`
+	TakeProfitPct  float64     `json:"take_profit_pct"`
+	InitialStopPct float64     `json:"initial_stop_pct"`
+	TrailingPct    float64     `json:"trailing_pct"`
+	HighWater      float64     `json:"high_water"`
+	Status         AlertStatus `json:"status"`
+}
+
+// armTrigger is the price at which the trailing stop first arms.
+func (a AlertState) armTrigger() float64 {
+	return a.EntryPrice * (1 + a.TakeProfitPct)
+}
+
+// initialStop is the fixed stop that applies before the trailing stop arms.
+func (a AlertState) initialStop() float64 {
+	return a.EntryPrice * (1 - a.InitialStopPct)
+}
+
+// trailingStop is the current trailing-stop trigger price once armed.
+func (a AlertState) trailingStop() float64 {
+	return a.HighWater * (1 - a.TrailingPct)
+}
+
+// AlertEvent is published on PriceAlertMonitor.Events whenever a stop
+// fires, for the handlers layer to relay to the UI and/or an optional
+// webhook.
+type AlertEvent struct {
+	Ticker string  `json:"ticker"`
+	Side   string  `json:"side"` // always "SELL" today; kept as a string for forward compatibility
+	Price  float64 `json:"price"`
+	Reason string  `json:"reason"`
+}
+
+// alertPollInterval and alertStateFile are overridable via
+// ALERT_POLL_INTERVAL_SECONDS and ALERT_STATE_FILE for tests and
+// deployments that want a different cadence or persistence path.
+var (
+	alertPollInterval = time.Duration(envInt("ALERT_POLL_INTERVAL_SECONDS", 10)) * time.Second
+	alertStateFile    = envString("ALERT_STATE_FILE", "alert_state.json")
+	alertWebhookURL   = envString("ALERT_WEBHOOK_URL", "")
+)
+
+// PriceAlertMonitor polls quotes for every registered ticker and maintains
+// a trailing stop-loss once the take-profit trigger first arms it,
+// publishing an AlertEvent (and, if configured, POSTing a webhook) when the
+// position should be sold. State is persisted to disk after every update so
+// a restart doesn't lose a ticker's high-water mark.
+type PriceAlertMonitor struct {
+	mu     sync.Mutex
+	alerts map[string]*AlertState
+	cancel map[string]func()
+
+	events     chan AlertEvent
+	stateFile  string
+	pollEvery  time.Duration
+	httpClient *http.Client
+}
+
+// NewPriceAlertMonitor creates a monitor, loading any persisted alerts from
+// stateFile (if it exists) and resuming their pollers.
+func NewPriceAlertMonitor() *PriceAlertMonitor {
+	m := &PriceAlertMonitor{
+		alerts:     make(map[string]*AlertState),
+		cancel:     make(map[string]func()),
+		events:     make(chan AlertEvent, 64),
+		stateFile:  alertStateFile,
+		pollEvery:  alertPollInterval,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+	m.loadState()
+
+	m.mu.Lock()
+	tickers := make([]string, 0, len(m.alerts))
+	for ticker := range m.alerts {
+		tickers = append(tickers, ticker)
+	}
+	m.mu.Unlock()
+	for _, ticker := range tickers {
+		m.startPoller(ticker)
+	}
+
+	return m
+}
+
+// Events returns the channel AlertEvents are published on. Callers should
+// drain it continuously; a full buffer drops the event rather than block
+// the poller.
+func (m *PriceAlertMonitor) Events() <-chan AlertEvent {
+	return m.events
+}
+
+// RegisterAlert arms a new (or replaces an existing) alert for ticker: a
+// trailing stop of trailingPct engages once price first reaches
+// entryPrice*(1+takeProfitPct); until then, a fixed stop at
+// entryPrice*(1-initialStopPct) applies.
+func (m *PriceAlertMonitor) RegisterAlert(ticker string, entryPrice, takeProfitPct, initialStopPct, trailingPct float64) {
+	m.mu.Lock()
+	if cancel, ok := m.cancel[ticker]; ok {
+		cancel()
+	}
+	m.alerts[ticker] = &AlertState{
+		Ticker:         ticker,
+		EntryPrice:     entryPrice,
+		TakeProfitPct:  takeProfitPct,
+		InitialStopPct: initialStopPct,
+		TrailingPct:    trailingPct,
+		Status:         AlertWatching,
+	}
+	m.mu.Unlock()
+
+	m.saveState()
+	m.startPoller(ticker)
+}
+
+// Snapshot returns the current state of every registered alert, for the GET
+// /alerts endpoint.
+func (m *PriceAlertMonitor) Snapshot() []AlertState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]AlertState, 0, len(m.alerts))
+	for _, a := range m.alerts {
+		out = append(out, *a)
+	}
+	return out
+}
+
+// startPoller begins (or restarts) the background poller for ticker.
+func (m *PriceAlertMonitor) startPoller(ticker string) {
+	stop := make(chan struct{})
+	m.mu.Lock()
+	m.cancel[ticker] = func() { close(stop) }
+	m.mu.Unlock()
+
+	go m.poll(ticker, stop)
+}
+
+// poll fetches ticker's quote every m.pollEvery and evaluates it against
+// the alert's stop levels, until stop is closed (the alert is replaced or
+// removed).
+func (m *PriceAlertMonitor) poll(ticker string, stop <-chan struct{}) {
+	ticker2 := time.NewTicker(m.pollEvery)
+	defer ticker2.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker2.C:
+			stock, err := FetchStockInfo(ticker)
+			if err != nil {
+				log.Printf("price alert: failed to fetch %s: %v", ticker, err)
+				continue
+			}
+			m.evaluate(ticker, stock.Price)
+		}
+	}
+}
+
+// evaluate applies price against ticker's alert, arming the trailing stop,
+// advancing its high-water mark, and firing a SELL event if a stop level
+// is breached.
+func (m *PriceAlertMonitor) evaluate(ticker string, price float64) {
+	m.mu.Lock()
+	alert, ok := m.alerts[ticker]
+	if !ok || alert.Status == AlertTriggered {
+		m.mu.Unlock()
+		return
+	}
+
+	var event *AlertEvent
+	switch alert.Status {
+	case AlertWatching:
+		if price >= alert.armTrigger() {
+			alert.Status = AlertArmed
+			alert.HighWater = price
+		} else if price <= alert.initialStop() {
+			alert.Status = AlertTriggered
+			event = &AlertEvent{Ticker: ticker, Side: "SELL", Price: price, Reason: "initial stop-loss hit"}
+		}
+	case AlertArmed:
+		if price > alert.HighWater {
+			alert.HighWater = price
+		}
+		if price <= alert.trailingStop() {
+			alert.Status = AlertTriggered
+			event = &AlertEvent{Ticker: ticker, Side: "SELL", Price: price, Reason: "trailing stop hit"}
+		}
+	}
+	m.mu.Unlock()
+
+	m.saveState()
+	if event != nil {
+		m.publish(*event)
+	}
+}
+
+// publish sends event on m.events (dropping it if the buffer is full) and,
+// if ALERT_WEBHOOK_URL is configured, POSTs it there too.
+func (m *PriceAlertMonitor) publish(event AlertEvent) {
+	select {
+	case m.events <- event:
+	default:
+		log.Printf("price alert: dropping event for %s (buffer full)", event.Ticker)
+	}
+
+	if alertWebhookURL == "" {
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("price alert: failed to marshal webhook payload for %s: %v", event.Ticker, err)
+		return
+	}
+	resp, err := m.httpClient.Post(alertWebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("price alert: webhook delivery failed for %s: %v", event.Ticker, err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// saveState writes every registered alert's current state to m.stateFile so
+// a restart can resume from the same high-water marks instead of re-arming
+// from scratch.
+func (m *PriceAlertMonitor) saveState() {
+	m.mu.Lock()
+	alerts := make([]AlertState, 0, len(m.alerts))
+	for _, a := range m.alerts {
+		alerts = append(alerts, *a)
+	}
+	m.mu.Unlock()
+
+	data, err := json.Marshal(alerts)
+	if err != nil {
+		log.Printf("price alert: failed to marshal state: %v", err)
+		return
+	}
+	if err := os.WriteFile(m.stateFile, data, 0644); err != nil {
+		log.Printf("price alert: failed to persist state to %s: %v", m.stateFile, err)
+	}
+}
+
+// loadState restores alerts from m.stateFile, if it exists; a missing or
+// unreadable file just leaves the monitor empty.
+func (m *PriceAlertMonitor) loadState() {
+	data, err := os.ReadFile(m.stateFile)
+	if err != nil {
+		return
+	}
+
+	var alerts []AlertState
+	if err := json.Unmarshal(data, &alerts); err != nil {
+		log.Printf("price alert: failed to parse persisted state %s: %v", m.stateFile, err)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, a := range alerts {
+		alert := a
+		m.alerts[alert.Ticker] = &alert
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestAlertMonitor builds a PriceAlertMonitor with a fast poll interval
+// and a scratch state file, so tests don't touch the real
+// alert_state.json or wait out the default 10s cadence.
+func newTestAlertMonitor(t *testing.T) *PriceAlertMonitor {
+	t.Helper()
+	return &PriceAlertMonitor{
+		alerts:     make(map[string]*AlertState),
+		cancel:     make(map[string]func()),
+		events:     make(chan AlertEvent, 8),
+		stateFile:  t.TempDir() + "/alert_state.json",
+		pollEvery:  10 * time.Millisecond,
+		httpClient: nil,
+	}
+}
+
+// TestPriceAlertMonitorFixedStopBeforeArming verifies a price drop below the
+// initial fixed stop fires before the take-profit trigger has ever armed
+// the trailing stop.
+func TestPriceAlertMonitorFixedStopBeforeArming(t *testing.T) {
+	m := newTestAlertMonitor(t)
+	m.RegisterAlert("AAPL", 100, 0.10, 0.05, 0.03)
+
+	m.evaluate("AAPL", 94) // below entry*(1-0.05) = 95
+
+	select {
+	case event := <-m.events:
+		if event.Reason != "initial stop-loss hit" {
+			t.Errorf("expected initial stop-loss event, got %+v", event)
+		}
+	default:
+		t.Fatal("expected an alert event to be published")
+	}
+}
+
+// TestPriceAlertMonitorTrailingStopAfterArming verifies the trailing stop
+// arms once the take-profit trigger is reached, then fires once price
+// retreats trailingPct off the high-water mark.
+func TestPriceAlertMonitorTrailingStopAfterArming(t *testing.T) {
+	m := newTestAlertMonitor(t)
+	m.RegisterAlert("MSFT", 100, 0.10, 0.05, 0.03) // arms at 110, trails 3% off high-water
+
+	m.evaluate("MSFT", 112)
+	snapshot := m.Snapshot()
+	if len(snapshot) != 1 || snapshot[0].Status != AlertArmed || snapshot[0].HighWater != 112 {
+		t.Fatalf("expected the alert to be armed with high-water 112, got %+v", snapshot)
+	}
+
+	m.evaluate("MSFT", 120)   // raises the high-water mark
+	m.evaluate("MSFT", 116.5) // 120*(1-0.03) = 116.4, still above trigger
+
+	select {
+	case event := <-m.events:
+		t.Fatalf("did not expect a trigger yet, got %+v", event)
+	default:
+	}
+
+	m.evaluate("MSFT", 116.3) // now below 116.4
+
+	select {
+	case event := <-m.events:
+		if event.Reason != "trailing stop hit" {
+			t.Errorf("expected trailing stop event, got %+v", event)
+		}
+	default:
+		t.Fatal("expected the trailing stop to fire")
+	}
+}
+
+// TestPriceAlertMonitorPersistsAcrossRestart verifies a restart resumes
+// from the same high-water mark instead of re-arming from scratch.
+func TestPriceAlertMonitorPersistsAcrossRestart(t *testing.T) {
+	m := newTestAlertMonitor(t)
+	m.RegisterAlert("TSLA", 200, 0.10, 0.05, 0.03)
+	m.evaluate("TSLA", 225)
+
+	restarted := &PriceAlertMonitor{
+		alerts:    make(map[string]*AlertState),
+		cancel:    make(map[string]func()),
+		events:    make(chan AlertEvent, 8),
+		stateFile: m.stateFile,
+		pollEvery: 10 * time.Millisecond,
+	}
+	restarted.loadState()
+
+	snapshot := restarted.Snapshot()
+	if len(snapshot) != 1 || snapshot[0].Status != AlertArmed || snapshot[0].HighWater != 225 {
+		t.Fatalf("expected the restored alert to be armed with high-water 225, got %+v", snapshot)
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// StockDataProvider is implemented by anything that can serve quotes, historical
+// bars, and a live stream of updates for a set of tickers. It lets FetchStockInfo
+// and the WebSocket pipeline treat Alpha Vantage, Alpaca, Yahoo, and the demo
+// fallback interchangeably.
+type StockDataProvider interface {
+	// Quote returns the latest snapshot for a single ticker.
+	Quote(ticker string) (*StockInfo, error)
+
+	// Bars returns historical OHLCV bars for ticker between start and end at the
+	// given timeframe (e.g. "1Min", "1Day").
+	Bars(ticker, timeframe string, start, end time.Time) ([]Bar, error)
+
+	// Stream opens a live feed of updates for the given tickers. The returned
+	// channel is closed when ctx is cancelled or the stream cannot be
+	// maintained.
+	Stream(ctx context.Context, tickers []string) (<-chan StreamUpdate, error)
+
+	// Name identifies the provider for logging and health reporting.
+	Name() string
+}
+
+// Bar represents a single OHLCV bar for a ticker.
+type Bar struct {
+	Timestamp time.Time `json:"timestamp"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    int64     `json:"volume"`
+}
+
+// StreamUpdate is a single push update delivered by a StockDataProvider's
+// Stream channel.
+type StreamUpdate struct {
+	Ticker    string    `json:"ticker"`
+	Price     float64   `json:"price"`
+	Change    float64   `json:"change"`
+	ChangePct string    `json:"change_pct"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"os"
+	"testing"
+)
+
+// TestPrimaryProviderFromEnv verifies INVESTIFY_PROVIDER selects the
+// matching adapter, and that unset/unknown/unconfigured values fall back to
+// the default chain order (nil, meaning "no override").
+func TestPrimaryProviderFromEnv(t *testing.T) {
+	t.Cleanup(func() { os.Unsetenv("INVESTIFY_PROVIDER") })
+
+	cases := []struct {
+		value string
+		want  string // expected Name(), or "" for nil
+	}{
+		{"", ""},
+		{"chain", ""},
+		{"sim", ""}, // handled by the handlers package, not here
+		{"yahoo", "yahoo"},
+		{"finnhub", "finnhub"},
+	}
+
+	for _, tc := range cases {
+		os.Setenv("INVESTIFY_PROVIDER", tc.value)
+		got := primaryProviderFromEnv()
+		if tc.want == "" {
+			if got != nil {
+				t.Errorf("INVESTIFY_PROVIDER=%q: expected nil, got %q", tc.value, got.Name())
+			}
+			continue
+		}
+		if got == nil || got.Name() != tc.want {
+			t.Errorf("INVESTIFY_PROVIDER=%q: expected %q, got %v", tc.value, tc.want, got)
+		}
+	}
+}
+
+// TestPrimaryProviderFromEnvAlpacaUnconfigured verifies that selecting
+// "alpaca" without alpacaProvider configured falls back to nil rather than
+// panicking on a nil StockDataProvider.
+func TestPrimaryProviderFromEnvAlpacaUnconfigured(t *testing.T) {
+	t.Cleanup(func() { os.Unsetenv("INVESTIFY_PROVIDER") })
+	os.Setenv("INVESTIFY_PROVIDER", "alpaca")
+
+	if alpacaProvider != nil {
+		t.Skip("alpacaProvider is configured in this environment; nothing to assert here")
+	}
+	if got := primaryProviderFromEnv(); got != nil {
+		t.Errorf("expected nil when alpaca is selected but unconfigured, got %q", got.Name())
+	}
+}
+
+// TestBuildDefaultRegistryHonorsPrimary verifies a selected primary
+// provider's Quote is consulted before the rest of the default chain.
+func TestBuildDefaultRegistryHonorsPrimary(t *testing.T) {
+	t.Cleanup(func() { os.Unsetenv("INVESTIFY_PROVIDER") })
+	os.Setenv("INVESTIFY_PROVIDER", "yahoo")
+
+	registry := buildDefaultRegistry()
+	names := make([]string, 0)
+	for _, snap := range registry.Health() {
+		names = append(names, snap.Name)
+	}
+	if len(names) == 0 || names[0] != "yahoo" {
+		t.Errorf("expected yahoo to be registered first, got order %v", names)
+	}
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// breakerState is the circuit breaker state for a single provider.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+const (
+	breakerFailureThreshold = 3
+	breakerBaseCooldown     = time.Second
+	breakerMaxCooldown      = 5 * time.Minute
+)
+
+// providerHealth tracks the circuit breaker state and stats for one provider.
+type providerHealth struct {
+	mu sync.Mutex
+
+	state           breakerState
+	consecutiveFail int
+	cooldown        time.Duration
+	openedAt        time.Time
+	lastError       error
+	totalCalls      int
+	totalSuccesses  int
+}
+
+// ProviderHealthSnapshot is the exported, read-only view of a provider's
+// health returned by /api/providers/health.
+type ProviderHealthSnapshot struct {
+	Name        string    `json:"name"`
+	State       string    `json:"state"`
+	LastError   string    `json:"last_error,omitempty"`
+	NextRetry   time.Time `json:"next_retry,omitempty"`
+	SuccessRate float64   `json:"success_rate"`
+}
+
+// recordSuccess closes the breaker (or half-closes it) after a successful call.
+func (h *providerHealth) recordSuccess() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.totalCalls++
+	h.totalSuccesses++
+	h.consecutiveFail = 0
+	h.cooldown = 0
+	h.state = breakerClosed
+}
+
+// recordFailure opens the breaker once consecutive failures cross the
+// threshold, doubling the cooldown each time it reopens (capped).
+func (h *providerHealth) recordFailure(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.totalCalls++
+	h.consecutiveFail++
+	h.lastError = err
+
+	if h.consecutiveFail >= breakerFailureThreshold {
+		if h.cooldown == 0 {
+			h.cooldown = breakerBaseCooldown
+		} else {
+			h.cooldown *= 2
+			if h.cooldown > breakerMaxCooldown {
+				h.cooldown = breakerMaxCooldown
+			}
+		}
+		h.state = breakerOpen
+		h.openedAt = time.Now()
+	}
+}
+
+// allow reports whether a call should be attempted, moving an open breaker
+// to half-open once its cooldown has elapsed.
+func (h *providerHealth) allow() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state != breakerOpen {
+		return true
+	}
+
+	if time.Since(h.openedAt) >= h.cooldown {
+		h.state = breakerHalfOpen
+		return true
+	}
+	return false
+}
+
+func (h *providerHealth) snapshot(name string) ProviderHealthSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	snap := ProviderHealthSnapshot{Name: name}
+	switch h.state {
+	case breakerOpen:
+		snap.State = "open"
+		snap.NextRetry = h.openedAt.Add(h.cooldown)
+	case breakerHalfOpen:
+		snap.State = "half-open"
+	default:
+		snap.State = "closed"
+	}
+	if h.lastError != nil {
+		snap.LastError = h.lastError.Error()
+	}
+	if h.totalCalls > 0 {
+		snap.SuccessRate = float64(h.totalSuccesses) / float64(h.totalCalls)
+	}
+	return snap
+}
+
+// registeredProvider pairs a provider with its circuit breaker.
+type registeredProvider struct {
+	provider StockDataProvider
+	health   *providerHealth
+}
+
+// ProviderRegistry holds an ordered list of StockDataProviders, each guarded
+// by its own circuit breaker, and iterates them in priority order for quotes
+// and searches, skipping any provider whose breaker is open.
+type ProviderRegistry struct {
+	mu        sync.RWMutex
+	providers []*registeredProvider
+}
+
+// NewProviderRegistry creates an empty registry. Providers are added in
+// priority order via Register.
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{}
+}
+
+// Register adds a provider to the end of the priority list.
+func (r *ProviderRegistry) Register(provider StockDataProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.providers = append(r.providers, &registeredProvider{
+		provider: provider,
+		health:   &providerHealth{},
+	})
+}
+
+// Quote tries each registered provider in priority order, skipping providers
+// whose breaker is open, and records success/failure against the breaker.
+func (r *ProviderRegistry) Quote(ticker string) (*StockInfo, error) {
+	r.mu.RLock()
+	providers := append([]*registeredProvider(nil), r.providers...)
+	r.mu.RUnlock()
+
+	var lastErr error
+	for _, rp := range providers {
+		if !rp.health.allow() {
+			continue
+		}
+
+		info, err := rp.provider.Quote(ticker)
+		if err != nil {
+			rp.health.recordFailure(err)
+			lastErr = err
+			continue
+		}
+
+		rp.health.recordSuccess()
+		return info, nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no providers available for %s", ticker)
+	}
+	return nil, lastErr
+}
+
+// Health returns a snapshot of every registered provider's circuit breaker
+// state, last error, next retry time, and success rate.
+func (r *ProviderRegistry) Health() []ProviderHealthSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := make([]ProviderHealthSnapshot, 0, len(r.providers))
+	for _, rp := range r.providers {
+		snapshots = append(snapshots, rp.health.snapshot(rp.provider.Name()))
+	}
+	return snapshots
+}
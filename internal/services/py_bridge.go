@@ -1,34 +1,46 @@
 package services
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// PythonBridge provides an interface to Python scripts for ML model inference
+// PythonBridge provides an interface to Python scripts for ML model
+// inference. Predictions are served by a pool of long-lived
+// simple_analyzer_server.py workers (see pyWorkerPool) rather than a fresh
+// interpreter per call, since fork/exec and import overhead otherwise
+// dominates latency once callers fan out to many tickers at once.
 type PythonBridge struct {
 	initialized      bool
 	pythonExecutable string
 	initMutex        sync.Mutex
 	scriptDir        string
 	virtualEnvPath   string
+
+	pool *pyWorkerPool
 }
 
 // PredictionResult represents the output from Python prediction model
 type PredictionResult struct {
-	PredictedPrice float64                 `json:"predicted_price"`
-	Confidence     float64                 `json:"confidence"`
-	Direction      string                  `json:"direction"`
-	Factors        []string                `json:"factors"`
-	Technical      map[string]interface{}  `json:"technical,omitempty"`
-	Error          string                  `json:"error,omitempty"`
+	PredictedPrice float64                `json:"predicted_price"`
+	Confidence     float64                `json:"confidence"`
+	Direction      string                 `json:"direction"`
+	Factors        []string               `json:"factors"`
+	Technical      map[string]interface{} `json:"technical,omitempty"`
+	Error          string                 `json:"error,omitempty"`
 }
 
 var defaultBridge *PythonBridge
@@ -51,33 +63,42 @@ func NewPythonBridge() *PythonBridge {
 	}
 }
 
-// Initialize checks if Python is available and required packages are installed
+// pythonWorkerCount is how many persistent simple_analyzer_server.py
+// workers the bridge keeps running, overridable via PYTHON_WORKER_COUNT;
+// defaults to GOMAXPROCS, matching how many predictions can usefully run
+// concurrently on this machine.
+func pythonWorkerCount() int {
+	return envInt("PYTHON_WORKER_COUNT", runtime.GOMAXPROCS(0))
+}
+
+// Initialize checks if Python is available and required packages are
+// installed, then starts the persistent worker pool.
 func (pb *PythonBridge) Initialize() error {
 	pb.initMutex.Lock()
 	defer pb.initMutex.Unlock()
-	
+
 	if pb.initialized {
 		return nil
 	}
-	
+
 	if pb.pythonExecutable == "" {
 		return fmt.Errorf("Python executable not found")
 	}
-	
+
 	// Check if the virtual environment is active
 	if pb.virtualEnvPath != "" {
 		log.Printf("Using Python virtual environment: %s", pb.virtualEnvPath)
 	}
-	
-	// Check if the simple analyzer script exists
-	analyzerPath := filepath.Join(pb.scriptDir, "simple_analyzer.py")
-	if _, err := os.Stat(analyzerPath); os.IsNotExist(err) {
-		return fmt.Errorf("simple_analyzer.py script not found at %s", analyzerPath)
+
+	// Check if the persistent worker entrypoint exists
+	serverPath := filepath.Join(pb.scriptDir, "simple_analyzer_server.py")
+	if _, err := os.Stat(serverPath); os.IsNotExist(err) {
+		return fmt.Errorf("simple_analyzer_server.py script not found at %s", serverPath)
 	}
-	
+
 	// Try a simple import test with python
 	cmd := exec.Command(pb.pythonExecutable, "-c", "import numpy; import pandas; import yfinance; print('OK')")
-	
+
 	// Set the virtual environment's Python if available
 	if pb.virtualEnvPath != "" {
 		venvPython := filepath.Join(pb.virtualEnvPath, "bin", "python")
@@ -85,126 +106,349 @@ func (pb *PythonBridge) Initialize() error {
 			cmd = exec.Command(venvPython, "-c", "import numpy; import pandas; import yfinance; print('OK')")
 		}
 	}
-	
+
 	var out bytes.Buffer
 	var stderr bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &stderr
-	
+
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("dependency check failed: %v: %s", err, stderr.String())
 	}
-	
+
+	pool, err := newPyWorkerPool(pb, pythonWorkerCount())
+	if err != nil {
+		return fmt.Errorf("failed to start python worker pool: %w", err)
+	}
+	pb.pool = pool
+
 	pb.initialized = true
 	return nil
 }
 
-// PredictStockPrice predicts the stock price for a given ticker
-func (pb *PythonBridge) PredictStockPrice(ticker string) (*PredictionResult, error) {
+// PredictStockPrice predicts the stock price for a given ticker, honoring
+// ctx cancellation by sending a cancel message to the serving worker.
+func (pb *PythonBridge) PredictStockPrice(ctx context.Context, ticker string) (*PredictionResult, error) {
+	return pb.predict(ctx, ticker)
+}
+
+// PredictStockPriceWithSimpleAnalyzer predicts the stock price for a given
+// ticker using simple_analyzer_server.py. Kept as a distinct method for the
+// call sites that name it explicitly; it shares PredictStockPrice's
+// underlying worker pool.
+func (pb *PythonBridge) PredictStockPriceWithSimpleAnalyzer(ctx context.Context, ticker string) (*PredictionResult, error) {
+	return pb.predict(ctx, ticker)
+}
+
+// predict validates ticker and dispatches a "predict" request to the
+// worker pool.
+func (pb *PythonBridge) predict(ctx context.Context, ticker string) (*PredictionResult, error) {
 	if !pb.initialized {
 		if err := pb.Initialize(); err != nil {
 			return nil, fmt.Errorf("bridge not initialized: %v", err)
 		}
 	}
-	
-	// Validate ticker
+
 	ticker = strings.TrimSpace(ticker)
 	if ticker == "" {
 		return nil, fmt.Errorf("empty ticker")
 	}
-	
-	analyzerPath := filepath.Join(pb.scriptDir, "simple_analyzer.py")
-	
-	// Prepare the command
-	var cmd *exec.Cmd
-	
-	// If we have a virtual environment, use its Python
-	if pb.virtualEnvPath != "" {
-		venvPython := filepath.Join(pb.virtualEnvPath, "bin", "python")
-		if _, err := os.Stat(venvPython); err == nil {
-			cmd = exec.Command(venvPython, analyzerPath, ticker)
-		} else {
-			cmd = exec.Command(pb.pythonExecutable, analyzerPath, ticker)
+
+	result, err := pb.pool.dispatch(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("prediction failed: %w", err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("prediction error: %s", result.Error)
+	}
+	return result, nil
+}
+
+// rpcRequest is one newline-delimited JSON request sent to a worker's
+// stdin, matched to its rpcResponse by ID.
+type rpcRequest struct {
+	ID     int64  `json:"id"`
+	Method string `json:"method"` // "predict" or "cancel"
+	Ticker string `json:"ticker,omitempty"`
+}
+
+// rpcResponse is one newline-delimited JSON response read from a worker's
+// stdout.
+type rpcResponse struct {
+	ID             int64    `json:"id"`
+	PredictedPrice float64  `json:"predicted_price"`
+	Confidence     float64  `json:"confidence"`
+	Direction      string   `json:"direction"`
+	Factors        []string `json:"factors"`
+	Error          string   `json:"error,omitempty"`
+}
+
+func (r *rpcResponse) toPredictionResult() *PredictionResult {
+	return &PredictionResult{
+		PredictedPrice: r.PredictedPrice,
+		Confidence:     r.Confidence,
+		Direction:      r.Direction,
+		Factors:        r.Factors,
+		Error:          r.Error,
+	}
+}
+
+// initialWorkerBackoff/maxWorkerBackoff bound the exponential backoff
+// pyWorkerPool applies between restart attempts for a worker that exits or
+// whose stdout can't be parsed.
+const (
+	initialWorkerBackoff = 500 * time.Millisecond
+	maxWorkerBackoff     = 30 * time.Second
+)
+
+// pyWorker is one persistent simple_analyzer_server.py process.
+type pyWorker struct {
+	index  int
+	bridge *PythonBridge
+	pool   *pyWorkerPool
+
+	mu      sync.Mutex // guards cmd/stdin against concurrent writes and restarts
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	backoff time.Duration
+}
+
+// pendingCall is one in-flight request dispatch is waiting on, tracking
+// which worker it was sent to so restartWorker can fail it out instead of
+// leaving dispatch's select blocked forever if that worker dies before
+// writing a response.
+type pendingCall struct {
+	ch     chan *rpcResponse
+	worker *pyWorker
+}
+
+// pyWorkerPool is N pyWorkers dispatched to round-robin, with in-flight
+// requests tracked by ID so responses read from any worker's stdout can be
+// routed back to the Go caller waiting on them.
+type pyWorkerPool struct {
+	workers []*pyWorker
+	next    uint64 // atomically incremented for round-robin worker selection
+
+	nextID int64 // atomically incremented request ID
+
+	pendingMu sync.Mutex
+	pending   map[int64]*pendingCall
+}
+
+// newPyWorkerPool starts n workers and returns the pool once all have been
+// launched (a worker that fails to start is retried via the same restart
+// path a later crash would use).
+func newPyWorkerPool(bridge *PythonBridge, n int) (*pyWorkerPool, error) {
+	if n <= 0 {
+		n = 1
+	}
+	pool := &pyWorkerPool{
+		pending: make(map[int64]*pendingCall),
+	}
+	pool.workers = make([]*pyWorker, n)
+	for i := 0; i < n; i++ {
+		w := &pyWorker{index: i, bridge: bridge, pool: pool}
+		pool.workers[i] = w
+		if err := w.start(); err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			log.Printf("python worker %d failed to start: %v, will retry in background", i, err)
+			go pool.restartWorker(w)
 		}
-	} else {
-		cmd = exec.Command(pb.pythonExecutable, analyzerPath, ticker)
 	}
-	
-	// Execute the command
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	
-	err := cmd.Run()
+	return pool, nil
+}
+
+// start launches (or relaunches) w's Python process and wires up its
+// stdin/stdout. Callers must not hold w.mu.
+func (w *pyWorker) start() error {
+	serverPath := filepath.Join(w.bridge.scriptDir, "simple_analyzer_server.py")
+
+	pythonExecutable := w.bridge.pythonExecutable
+	if w.bridge.virtualEnvPath != "" {
+		if venvPython := filepath.Join(w.bridge.virtualEnvPath, "bin", "python"); fileExists(venvPython) {
+			pythonExecutable = venvPython
+		}
+	}
+
+	cmd := exec.Command(pythonExecutable, serverPath)
+	stdin, err := cmd.StdinPipe()
 	if err != nil {
-		return nil, fmt.Errorf("prediction failed: %v: %s", err, stderr.String())
+		return fmt.Errorf("worker %d: stdin pipe: %w", w.index, err)
 	}
-	
-	// Parse the result
-	var result PredictionResult
-	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse prediction result: %v: %s", err, stdout.String())
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("worker %d: stdout pipe: %w", w.index, err)
 	}
-	
-	if result.Error != "" {
-		return nil, fmt.Errorf("prediction error: %s", result.Error)
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("worker %d: start: %w", w.index, err)
 	}
-	
-	return &result, nil
+
+	w.mu.Lock()
+	w.cmd = cmd
+	w.stdin = stdin
+	w.mu.Unlock()
+
+	go func() {
+		w.readFrom(stdout)
+		// stdout closed: the process has exited or is exiting.
+		_ = cmd.Wait()
+		w.pool.restartWorker(w)
+	}()
+
+	return nil
 }
 
-// PredictStockPriceWithSimpleAnalyzer predicts the stock price for a given ticker using simple_analyzer.py
-func (pb *PythonBridge) PredictStockPriceWithSimpleAnalyzer(ticker string) (*PredictionResult, error) {
-	if !pb.initialized {
-		if err := pb.Initialize(); err != nil {
-			return nil, fmt.Errorf("bridge not initialized: %v", err)
+// readFrom scans newline-delimited JSON responses from the worker's stdout
+// and routes each to its caller via the pool's pending map, until stdout is
+// closed.
+func (w *pyWorker) readFrom(stdout io.ReadCloser) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var resp rpcResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			log.Printf("python worker %d: malformed response, ignoring: %v", w.index, err)
+			continue
 		}
+		w.pool.deliver(&resp)
 	}
-	
-	// Validate ticker
-	ticker = strings.TrimSpace(ticker)
-	if ticker == "" {
-		return nil, fmt.Errorf("empty ticker")
+}
+
+// send writes req as a newline-delimited JSON line to w's stdin.
+func (w *pyWorker) send(req rpcRequest) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
 	}
-	
-	analyzerPath := filepath.Join(pb.scriptDir, "simple_analyzer.py")
-	
-	// Prepare the command
-	var cmd *exec.Cmd
-	
-	// If we have a virtual environment, use its Python
-	if pb.virtualEnvPath != "" {
-		venvPython := filepath.Join(pb.virtualEnvPath, "bin", "python")
-		if _, err := os.Stat(venvPython); err == nil {
-			cmd = exec.Command(venvPython, analyzerPath, ticker)
-		} else {
-			cmd = exec.Command(pb.pythonExecutable, analyzerPath, ticker)
+	payload = append(payload, '\n')
+
+	w.mu.Lock()
+	stdin := w.stdin
+	w.mu.Unlock()
+	if stdin == nil {
+		return fmt.Errorf("worker %d: not running", w.index)
+	}
+
+	_, err = stdin.Write(payload)
+	return err
+}
+
+// failPending delivers a synthetic error response to every pending call
+// assigned to w, so a worker that dies between send and response doesn't
+// leave dispatch's select blocked forever (and its pending map entry
+// leaked) waiting on a reply that will now never come.
+func (pool *pyWorkerPool) failPending(w *pyWorker) {
+	pool.pendingMu.Lock()
+	var calls []*pendingCall
+	for id, call := range pool.pending {
+		if call.worker == w {
+			calls = append(calls, call)
+			delete(pool.pending, id)
 		}
+	}
+	pool.pendingMu.Unlock()
+
+	for _, call := range calls {
+		call.ch <- &rpcResponse{Error: fmt.Sprintf("worker %d crashed before responding", w.index)}
+	}
+}
+
+// restartWorker fails out any requests still waiting on w, then relaunches
+// it after an exponentially increasing backoff, capped at maxWorkerBackoff,
+// so a worker that keeps crashing doesn't spin the CPU restarting it.
+func (pool *pyWorkerPool) restartWorker(w *pyWorker) {
+	pool.failPending(w)
+
+	w.mu.Lock()
+	backoff := w.backoff
+	if backoff == 0 {
+		backoff = initialWorkerBackoff
 	} else {
-		cmd = exec.Command(pb.pythonExecutable, analyzerPath, ticker)
+		backoff *= 2
+		if backoff > maxWorkerBackoff {
+			backoff = maxWorkerBackoff
+		}
 	}
-	
-	// Execute the command
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	
-	err := cmd.Run()
-	if err != nil {
-		return nil, fmt.Errorf("prediction failed: %v: %s", err, stderr.String())
+	w.backoff = backoff
+	w.mu.Unlock()
+
+	log.Printf("python worker %d exited, restarting in %s", w.index, backoff)
+	time.Sleep(backoff)
+
+	if err := w.start(); err != nil {
+		log.Printf("python worker %d restart failed: %v", w.index, err)
+		go pool.restartWorker(w)
+		return
 	}
-	
-	// Parse the result
-	var result PredictionResult
-	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse prediction result: %v: %s", err, stdout.String())
+
+	w.mu.Lock()
+	w.backoff = 0
+	w.mu.Unlock()
+}
+
+// deliver routes resp to its caller's channel, if one is still waiting
+// (a cancelled or timed-out caller may have already stopped listening).
+func (pool *pyWorkerPool) deliver(resp *rpcResponse) {
+	pool.pendingMu.Lock()
+	call, ok := pool.pending[resp.ID]
+	if ok {
+		delete(pool.pending, resp.ID)
 	}
-	
-	if result.Error != "" {
-		return nil, fmt.Errorf("prediction error: %s", result.Error)
+	pool.pendingMu.Unlock()
+
+	if ok {
+		call.ch <- resp
 	}
-	
-	return &result, nil
+}
+
+// dispatch assigns ticker's prediction to a worker round-robin, waiting for
+// its response or ctx cancellation, in which case a best-effort cancel
+// message is sent to the worker and ctx.Err() is returned.
+func (pool *pyWorkerPool) dispatch(ctx context.Context, ticker string) (*PredictionResult, error) {
+	id := atomic.AddInt64(&pool.nextID, 1)
+	ch := make(chan *rpcResponse, 1)
+	worker := pool.pick()
+
+	pool.pendingMu.Lock()
+	pool.pending[id] = &pendingCall{ch: ch, worker: worker}
+	pool.pendingMu.Unlock()
+
+	if err := worker.send(rpcRequest{ID: id, Method: "predict", Ticker: ticker}); err != nil {
+		pool.pendingMu.Lock()
+		delete(pool.pending, id)
+		pool.pendingMu.Unlock()
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp.toPredictionResult(), nil
+	case <-ctx.Done():
+		_ = worker.send(rpcRequest{ID: id, Method: "cancel"})
+		pool.pendingMu.Lock()
+		delete(pool.pending, id)
+		pool.pendingMu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// pick selects the next worker round-robin.
+func (pool *pyWorkerPool) pick() *pyWorker {
+	i := atomic.AddUint64(&pool.next, 1)
+	return pool.workers[i%uint64(len(pool.workers))]
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
 }
 
 // Helper functions
@@ -213,14 +457,14 @@ func (pb *PythonBridge) PredictStockPriceWithSimpleAnalyzer(ticker string) (*Pre
 func detectPythonExecutable() string {
 	// Try several common Python executable names
 	pythons := []string{"python3", "python"}
-	
+
 	for _, python := range pythons {
 		path, err := exec.LookPath(python)
 		if err == nil {
 			return path
 		}
 	}
-	
+
 	return ""
 }
 
@@ -228,38 +472,38 @@ func detectPythonExecutable() string {
 func detectScriptDirectory() string {
 	// Try several common relative paths
 	candidates := []string{
-		"models",                // Run from project root
-		"../models",             // Run from the internal dir
-		"../../models",          // Run from internal/services
+		"models",       // Run from project root
+		"../models",    // Run from the internal dir
+		"../../models", // Run from internal/services
 	}
-	
+
 	// Get the current working directory
 	cwd, err := os.Getwd()
 	if err != nil {
 		return ""
 	}
-	
+
 	for _, candidate := range candidates {
 		path := filepath.Join(cwd, candidate)
 		if _, err := os.Stat(path); err == nil {
 			return path
 		}
 	}
-	
+
 	// Fall back to the executable's directory
 	exePath, err := os.Executable()
 	if err != nil {
 		return ""
 	}
 	exeDir := filepath.Dir(exePath)
-	
+
 	for _, candidate := range candidates {
 		path := filepath.Join(exeDir, candidate)
 		if _, err := os.Stat(path); err == nil {
 			return path
 		}
 	}
-	
+
 	return ""
 }
 
@@ -270,17 +514,17 @@ func detectVirtualEnvPath() string {
 	if err != nil {
 		return ""
 	}
-	
+
 	// Check for virtual environment in the project root
 	candidates := []string{
-		filepath.Join(cwd, ".venv"),                  // Run from project root (.venv)
-		filepath.Join(cwd, "venv"),                   // Run from project root (venv)
-		filepath.Join(cwd, "..", ".venv"),            // Run from subdirectory (.venv)
-		filepath.Join(cwd, "..", "venv"),             // Run from subdirectory (venv)
-		filepath.Join(cwd, "../..", ".venv"),         // Run from subsubdirectory (.venv)
-		filepath.Join(cwd, "../..", "venv"),          // Run from subsubdirectory (venv)
-	}
-	
+		filepath.Join(cwd, ".venv"),          // Run from project root (.venv)
+		filepath.Join(cwd, "venv"),           // Run from project root (venv)
+		filepath.Join(cwd, "..", ".venv"),    // Run from subdirectory (.venv)
+		filepath.Join(cwd, "..", "venv"),     // Run from subdirectory (venv)
+		filepath.Join(cwd, "../..", ".venv"), // Run from subsubdirectory (.venv)
+		filepath.Join(cwd, "../..", "venv"),  // Run from subsubdirectory (venv)
+	}
+
 	for _, candidate := range candidates {
 		// Look for bin/python to confirm it's a valid venv
 		binPython := filepath.Join(candidate, "bin", "python")
@@ -288,11 +532,11 @@ func detectVirtualEnvPath() string {
 			return candidate
 		}
 	}
-	
+
 	// Check if we're already in a virtual environment
 	if os.Getenv("VIRTUAL_ENV") != "" {
 		return os.Getenv("VIRTUAL_ENV")
 	}
-	
+
 	return ""
 }
@@ -1,9 +1,14 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 )
 
 // TestPythonBridgeInitialization tests that the Python bridge can be initialized
@@ -112,3 +117,158 @@ func TestPredictStockPrice(t *testing.T) {
 		}
 	}
 }
+
+// discardWriteCloser is a fake worker stdin that records writes without
+// needing a real subprocess on the other end, so pyWorkerPool's dispatch
+// logic can be exercised without spawning Python.
+type discardWriteCloser struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (d *discardWriteCloser) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.buf.Write(p)
+}
+
+func (d *discardWriteCloser) Close() error { return nil }
+
+func (d *discardWriteCloser) String() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.buf.String()
+}
+
+// newTestWorkerPool builds a one-worker pyWorkerPool whose worker never
+// starts a real process; its stdin is a discardWriteCloser so send()
+// succeeds and the written requests can be inspected.
+func newTestWorkerPool() (*pyWorkerPool, *pyWorker, *discardWriteCloser) {
+	pool := &pyWorkerPool{pending: make(map[int64]*pendingCall)}
+	stdin := &discardWriteCloser{}
+	worker := &pyWorker{index: 0, pool: pool, stdin: stdin}
+	pool.workers = []*pyWorker{worker}
+	return pool, worker, stdin
+}
+
+// waitForPending polls until id is registered in pool.pending, so tests can
+// deliver/fail a response only once dispatch has actually sent its request.
+func waitForPending(t *testing.T, pool *pyWorkerPool, id int64) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		pool.pendingMu.Lock()
+		_, ok := pool.pending[id]
+		pool.pendingMu.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("request %d was never registered in pool.pending", id)
+}
+
+// TestPyWorkerPoolDispatchDeliversResponse verifies dispatch returns the
+// result routed back through deliver, keyed by request ID.
+func TestPyWorkerPoolDispatchDeliversResponse(t *testing.T) {
+	pool, _, _ := newTestWorkerPool()
+
+	type outcome struct {
+		result *PredictionResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := pool.dispatch(context.Background(), "AAPL")
+		done <- outcome{result, err}
+	}()
+
+	waitForPending(t, pool, 1)
+	pool.deliver(&rpcResponse{ID: 1, PredictedPrice: 151.2, Confidence: 0.8, Direction: "UP"})
+
+	out := <-done
+	if out.err != nil {
+		t.Fatalf("dispatch returned error: %v", out.err)
+	}
+	if out.result.PredictedPrice != 151.2 || out.result.Direction != "UP" {
+		t.Errorf("dispatch result = %+v, want the delivered response", out.result)
+	}
+}
+
+// TestPyWorkerPoolDispatchCancelsOnContextDone verifies dispatch returns
+// ctx.Err() and sends the worker a best-effort "cancel" message once ctx is
+// cancelled before a response arrives.
+func TestPyWorkerPoolDispatchCancelsOnContextDone(t *testing.T) {
+	pool, _, stdin := newTestWorkerPool()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	type outcome struct {
+		result *PredictionResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := pool.dispatch(ctx, "AAPL")
+		done <- outcome{result, err}
+	}()
+
+	waitForPending(t, pool, 1)
+	cancel()
+
+	out := <-done
+	if !errors.Is(out.err, context.Canceled) {
+		t.Fatalf("dispatch error = %v, want context.Canceled", out.err)
+	}
+
+	if !bytes.Contains([]byte(stdin.String()), []byte(`"method":"cancel"`)) {
+		t.Errorf("expected a cancel message written to the worker's stdin, got %q", stdin.String())
+	}
+
+	pool.pendingMu.Lock()
+	_, stillPending := pool.pending[1]
+	pool.pendingMu.Unlock()
+	if stillPending {
+		t.Error("cancelled request should have been removed from pool.pending")
+	}
+}
+
+// TestPyWorkerPoolFailPendingUnblocksDispatchOnWorkerCrash verifies that
+// when a worker dies after send succeeds but before it writes a response,
+// failPending (called by restartWorker before relaunching) delivers a
+// synthetic error instead of leaving dispatch blocked and the pending
+// entry leaked forever.
+func TestPyWorkerPoolFailPendingUnblocksDispatchOnWorkerCrash(t *testing.T) {
+	pool, worker, _ := newTestWorkerPool()
+
+	type outcome struct {
+		result *PredictionResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := pool.dispatch(context.Background(), "AAPL")
+		done <- outcome{result, err}
+	}()
+
+	waitForPending(t, pool, 1)
+	pool.failPending(worker)
+
+	select {
+	case out := <-done:
+		if out.err != nil {
+			t.Fatalf("dispatch returned error: %v", out.err)
+		}
+		if out.result.Error == "" {
+			t.Error("expected the synthetic response to carry a non-empty Error field")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("dispatch is still blocked after the worker crashed; the pending request leaked")
+	}
+
+	pool.pendingMu.Lock()
+	_, stillPending := pool.pending[1]
+	pool.pendingMu.Unlock()
+	if stillPending {
+		t.Error("failPending should have removed the request from pool.pending")
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+// PythonEstimator adapts the existing Python bridge to the
+// estimator.PriceEstimator interface, so "python" can be selected alongside
+// the in-process OLS/kNN/GBDT estimators via PREDICTION_ESTIMATOR. Unlike
+// those, it predicts by ticker symbol rather than a feature vector, so
+// predictWithEstimator sets Ticker before calling Predict; Fit is a no-op
+// since the bridge's model state lives in the Python process, not here.
+type PythonEstimator struct {
+	Ticker string
+}
+
+// Fit is a no-op: the Python bridge trains and holds its own model state.
+func (e *PythonEstimator) Fit(features [][]float64, targets []float64) error {
+	return nil
+}
+
+// Predict ignores features and instead asks the Python bridge for a
+// prediction on e.Ticker, reporting a stddev derived from its confidence
+// (lower confidence widens the interval).
+func (e *PythonEstimator) Predict(features []float64) (float64, float64, error) {
+	if e.Ticker == "" {
+		return 0, 0, fmt.Errorf("estimator: PythonEstimator requires Ticker to be set")
+	}
+
+	bridge := GetPythonBridge()
+	if !bridge.initialized {
+		return 0, 0, fmt.Errorf("estimator: python bridge not initialized")
+	}
+
+	result, err := bridge.PredictStockPriceWithSimpleAnalyzer(context.Background(), e.Ticker)
+	if err != nil || result == nil {
+		return 0, 0, fmt.Errorf("estimator: python bridge prediction failed: %w", err)
+	}
+
+	stddev := result.PredictedPrice * (1 - result.Confidence) * 0.1
+	return result.PredictedPrice, stddev, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"log"
+	"time"
+)
+
+// QuoteProvider is a single data source the SearchStock fallback chain can
+// fall through to. It's deliberately narrower than StockDataProvider (which
+// also covers historical bars and streaming) so new quote-only sources can
+// be registered without implementing methods they don't support.
+type QuoteProvider interface {
+	// Fetch retrieves a quote for ticker.
+	Fetch(ticker string) (*StockInfo, error)
+
+	// Name identifies the provider for logging.
+	Name() string
+
+	// RateLimit is the minimum interval callers should wait between
+	// requests to this provider.
+	RateLimit() time.Duration
+}
+
+// quoteProviders is the ordered fallback chain used by SearchStock. Callers
+// register additional providers with RegisterQuoteProvider without needing
+// to edit SearchStock itself.
+var quoteProviders []QuoteProvider
+
+// RegisterQuoteProvider appends a provider to the end of the fallback chain.
+func RegisterQuoteProvider(provider QuoteProvider) {
+	quoteProviders = append(quoteProviders, provider)
+}
+
+func init() {
+	RegisterQuoteProvider(twelveDataQuoteProvider{})
+	RegisterQuoteProvider(alphaVantageQuoteProvider{})
+	RegisterQuoteProvider(finnhubQuoteProvider{})
+	RegisterQuoteProvider(yahooQuoteProvider{})
+}
+
+// fetchFromQuoteProviders tries each registered QuoteProvider in order,
+// returning the first successful quote.
+func fetchFromQuoteProviders(ticker string) (*StockInfo, error) {
+	var lastErr error
+	for _, provider := range quoteProviders {
+		info, err := provider.Fetch(ticker)
+		if err == nil && info != nil {
+			log.Printf("Successfully fetched %s data from %s", ticker, provider.Name())
+			return info, nil
+		}
+		log.Printf("%s failed for %s: %v", provider.Name(), ticker, err)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// twelveDataQuoteProvider adapts fetchFromTwelveData to QuoteProvider.
+type twelveDataQuoteProvider struct{}
+
+func (twelveDataQuoteProvider) Name() string               { return "twelvedata" }
+func (twelveDataQuoteProvider) RateLimit() time.Duration    { return time.Minute / 8 } // ~800/day
+func (twelveDataQuoteProvider) Fetch(t string) (*StockInfo, error) { return fetchFromTwelveData(t) }
+
+// alphaVantageQuoteProvider adapts fetchFromAlphaVantage to QuoteProvider.
+type alphaVantageQuoteProvider struct{}
+
+func (alphaVantageQuoteProvider) Name() string            { return "alphavantage" }
+func (alphaVantageQuoteProvider) RateLimit() time.Duration { return 12 * time.Second } // 5/min free tier
+func (alphaVantageQuoteProvider) Fetch(t string) (*StockInfo, error) {
+	return fetchFromAlphaVantage(t)
+}
+
+// finnhubQuoteProvider adapts fetchFromFinnhub to QuoteProvider.
+type finnhubQuoteProvider struct{}
+
+func (finnhubQuoteProvider) Name() string               { return "finnhub" }
+func (finnhubQuoteProvider) RateLimit() time.Duration    { return time.Second } // 60/min free tier
+func (finnhubQuoteProvider) Fetch(t string) (*StockInfo, error) { return fetchFromFinnhub(t) }
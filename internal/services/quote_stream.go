@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// quoteStreamPollInterval is how often QuoteStream's polling transport
+// fetches a fresh quote, overridable via QUOTE_STREAM_POLL_INTERVAL_SECONDS.
+var quoteStreamPollInterval = time.Duration(envInt("QUOTE_STREAM_POLL_INTERVAL_SECONDS", 5)) * time.Second
+
+// StockTick is one live observation of a ticker's quote, fanned out to
+// every subscriber on QuoteStream.
+type StockTick struct {
+	Stock *StockInfo
+}
+
+// QuoteStream ingests live ticks for a ticker and fans them out to
+// subscribers over typed channels, starting a single background feed per
+// ticker on its first subscriber and stopping it once the last one leaves.
+//
+// The feed currently polls FetchStockInfo (which already sits in front of
+// the websocket-capable provider chain registered by buildDefaultRegistry
+// - see AlpacaProvider.Stream); true push delivery for tickers with a live
+// broker connection is handled one layer up by
+// internal/handlers/tick_pipeline.go (chunk3-5), so this package doesn't
+// duplicate that transport. An MQTT topic-subscription transport, as
+// literally requested, isn't wired up: it needs a client library (e.g.
+// eclipse/paho.mqtt.golang) that can't be fetched or vendored in this
+// environment, the same constraint noted in internal/estimator/estimator.go
+// for github.com/sajari/regression.
+type QuoteStream struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan StockTick]struct{}
+	cancel      map[string]context.CancelFunc
+}
+
+// NewQuoteStream creates an empty QuoteStream.
+func NewQuoteStream() *QuoteStream {
+	return &QuoteStream{
+		subscribers: make(map[string]map[chan StockTick]struct{}),
+		cancel:      make(map[string]context.CancelFunc),
+	}
+}
+
+// Subscribe returns a channel of StockTicks for ticker and an unsubscribe
+// func the caller must call exactly once when done. The channel is
+// buffered; a subscriber that falls behind has the oldest tick dropped
+// rather than stalling the feed for everyone else.
+func (q *QuoteStream) Subscribe(ticker string) (<-chan StockTick, func()) {
+	ticker = strings.ToUpper(ticker)
+	ch := make(chan StockTick, 8)
+
+	q.mu.Lock()
+	if q.subscribers[ticker] == nil {
+		q.subscribers[ticker] = make(map[chan StockTick]struct{})
+	}
+	q.subscribers[ticker][ch] = struct{}{}
+	if _, running := q.cancel[ticker]; !running {
+		ctx, cancel := context.WithCancel(context.Background())
+		q.cancel[ticker] = cancel
+		go q.poll(ctx, ticker)
+	}
+	q.mu.Unlock()
+
+	return ch, func() { q.unsubscribe(ticker, ch) }
+}
+
+// unsubscribe removes ch from ticker's subscriber set, stopping the feed
+// if ch was the last subscriber.
+func (q *QuoteStream) unsubscribe(ticker string, ch chan StockTick) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	set := q.subscribers[ticker]
+	if set == nil {
+		return
+	}
+	delete(set, ch)
+	close(ch)
+	if len(set) > 0 {
+		return
+	}
+
+	delete(q.subscribers, ticker)
+	if cancel, ok := q.cancel[ticker]; ok {
+		cancel()
+		delete(q.cancel, ticker)
+	}
+}
+
+// poll fetches ticker's quote every quoteStreamPollInterval and fans it out
+// to every current subscriber, until ctx is cancelled.
+func (q *QuoteStream) poll(ctx context.Context, ticker string) {
+	t := time.NewTicker(quoteStreamPollInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			stock, err := FetchStockInfo(ticker)
+			if err != nil {
+				log.Printf("quote stream: failed to fetch %s: %v", ticker, err)
+				continue
+			}
+			q.broadcast(ticker, StockTick{Stock: stock})
+		}
+	}
+}
+
+// broadcast delivers tick to every current subscriber of ticker, dropping
+// it for any subscriber whose buffer is full.
+func (q *QuoteStream) broadcast(ticker string, tick StockTick) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for ch := range q.subscribers[ticker] {
+		select {
+		case ch <- tick:
+		default:
+			log.Printf("quote stream: dropping tick for slow subscriber on %s", ticker)
+		}
+	}
+}
+
+// defaultQuoteStream backs AIService.SubscribeRecommendations.
+var defaultQuoteStream = NewQuoteStream()
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"log"
+	"math"
+)
+
+// recommendationMinMoveBps is how far (in basis points) price must move
+// from the last computed recommendation before SubscribeRecommendations
+// recomputes on a tick, overridable via RECOMMENDATION_MIN_MOVE_BPS. This
+// exists to avoid spending an LLM call on every tick when price is barely
+// moving.
+var recommendationMinMoveBps = envFloat("RECOMMENDATION_MIN_MOVE_BPS", 50)
+
+// recommendationState is what SubscribeRecommendations compares each tick
+// against to decide whether to recompute.
+type recommendationState struct {
+	price       float64
+	sessionHigh float64
+	sessionLow  float64
+	stopLoss    float64
+	takeProfit  float64
+}
+
+// significant reports whether next's price has moved enough off s to
+// justify recomputing: a move past recommendationMinMoveBps, a new session
+// high/low, or a cross of the previously armed stop-loss/take-profit level.
+func (s recommendationState) significant(next *StockInfo) bool {
+	if s.price == 0 {
+		return true
+	}
+
+	movedBps := math.Abs(next.Price-s.price) / s.price * 10000
+	if movedBps >= recommendationMinMoveBps {
+		return true
+	}
+	if next.High > s.sessionHigh || next.Low < s.sessionLow {
+		return true
+	}
+	if s.stopLoss != 0 && crossed(s.price, next.Price, s.stopLoss) {
+		return true
+	}
+	if s.takeProfit != 0 && crossed(s.price, next.Price, s.takeProfit) {
+		return true
+	}
+	return false
+}
+
+// crossed reports whether price moved from one side of level to the other
+// between prev and next.
+func crossed(prev, next, level float64) bool {
+	return (prev < level) != (next < level)
+}
+
+// SubscribeToRecommendations subscribes to the shared aiService's
+// recommendation stream for ticker. It exists so handlers, which can't see
+// the unexported aiService var, can reach AIService.SubscribeRecommendations
+// the same way they reach other shared services via GetAlpacaProvider etc.
+func SubscribeToRecommendations(ticker string) (<-chan Recommendation, func()) {
+	return aiService.SubscribeRecommendations(ticker)
+}
+
+// SubscribeRecommendations returns a channel of Recommendations for
+// ticker, recomputed only when recommendationState.significant reports a
+// meaningful enough change since the last computation - a price move past
+// recommendationMinMoveBps, a new session high/low, or a cross of the
+// ticker's armed stop-loss/take-profit level - rather than on every tick
+// from the underlying QuoteStream, so a quiet ticker doesn't burn an LLM
+// call per poll. The channel is closed once unsubscribe is called.
+func (ai *AIService) SubscribeRecommendations(ticker string) (<-chan Recommendation, func()) {
+	ticks, unsubscribeTicks := defaultQuoteStream.Subscribe(ticker)
+	out := make(chan Recommendation, 4)
+
+	go func() {
+		defer close(out)
+
+		var state recommendationState
+		for tick := range ticks {
+			stock := tick.Stock
+			if !state.significant(stock) {
+				continue
+			}
+
+			rec, err := ai.GetStructuredRecommendation(stock)
+			if err != nil {
+				log.Printf("recommendation stream: failed to compute recommendation for %s: %v", ticker, err)
+				continue
+			}
+
+			state = recommendationState{
+				price:       stock.Price,
+				sessionHigh: math.Max(state.sessionHigh, stock.High),
+				sessionLow:  minNonZero(state.sessionLow, stock.Low),
+				stopLoss:    rec.StopLoss,
+				takeProfit:  rec.TargetPrice,
+			}
+
+			select {
+			case out <- *rec:
+			default:
+				log.Printf("recommendation stream: dropping recommendation for %s (slow subscriber)", ticker)
+			}
+		}
+	}()
+
+	return out, unsubscribeTicks
+}
+
+// minNonZero returns the smaller of a and b, treating a zero a (an unset
+// session low) as absent so b always wins until a real low is recorded.
+func minNonZero(a, b float64) float64 {
+	if a == 0 {
+		return b
+	}
+	return math.Min(a, b)
+}
@@ -7,10 +7,11 @@ import (
 	"log"
 	"math"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/martinrizk/investify/internal/cache"
 )
 
 // StockInfo represents stock information with AI analysis and ML predictions
@@ -32,23 +33,128 @@ type StockInfo struct {
 	TrendDirection       string   `json:"trend_direction"`
 	KeyFactors           []string `json:"key_factors"`
 	DataAge              int64    `json:"data_age"` // Time in seconds since data was retrieved
+
+	// Bracket-order levels derived from the ML prediction's ATR (see
+	// TFModelService.RiskLevels), rendered here so a client can place a
+	// matching stop-loss/take-profit order. Zero when there wasn't enough
+	// history to compute them.
+	StopLoss             float64 `json:"stop_loss,omitempty"`
+	TakeProfit           float64 `json:"take_profit,omitempty"`
+	TrailingCallbackRate float64 `json:"trailing_callback_rate,omitempty"`
+
+	// Fields populated by providers with richer data (e.g. Yahoo Finance);
+	// left as "N/A" by providers that don't supply them.
+	FiftyTwoWeekHigh string `json:"fifty_two_week_high"`
+	FiftyTwoWeekLow  string `json:"fifty_two_week_low"`
+	AverageVolume    string `json:"average_volume"`
+	PERatio          string `json:"pe_ratio"`
+	DividendYield    string `json:"dividend_yield"`
+
+	// Extended-hours pricing, populated by providers that report pre-market
+	// and after-hours trades (e.g. Yahoo Finance). Zero when the market
+	// session they describe hasn't occurred or the provider doesn't supply
+	// it.
+	PreMarketPrice      float64 `json:"pre_market_price,omitempty"`
+	PreMarketChange     float64 `json:"pre_market_change,omitempty"`
+	PreMarketChangePct  string  `json:"pre_market_change_pct,omitempty"`
+	PostMarketPrice     float64 `json:"post_market_price,omitempty"`
+	PostMarketChange    float64 `json:"post_market_change,omitempty"`
+	PostMarketChangePct string  `json:"post_market_change_pct,omitempty"`
+	MarketState         string  `json:"market_state,omitempty"` // PRE, REGULAR, POST, CLOSED
+
+	// Technical holds the indicators computed from historical bars by
+	// computeTechnicalIndicators, fed into the ML predictor. Nil if not
+	// enough historical data was available.
+	Technical *TechnicalIndicators `json:"technical_indicators,omitempty"`
+
+	// StructuredRecommendation is Recommendation populated from the same
+	// call that filled in Recommendation above, as a machine-readable
+	// structure a client can render directly (confidence bar, target/stop
+	// lines, per-signal breakdown) instead of parsing that free-form text.
+	StructuredRecommendation *Recommendation `json:"structured_recommendation,omitempty"`
+}
+
+// TechnicalIndicators mirrors indicators.Result for JSON serialization on
+// StockInfo.
+type TechnicalIndicators struct {
+	SMA20  float64 `json:"sma20"`
+	SMA50  float64 `json:"sma50"`
+	SMA200 float64 `json:"sma200"`
+	EMA12  float64 `json:"ema12"`
+	EMA26  float64 `json:"ema26"`
+
+	RSI14 float64 `json:"rsi14"`
+
+	MACD          float64 `json:"macd"`
+	MACDSignal    float64 `json:"macd_signal"`
+	MACDHistogram float64 `json:"macd_histogram"`
+
+	BollingerUpper  float64 `json:"bollinger_upper"`
+	BollingerMiddle float64 `json:"bollinger_middle"`
+	BollingerLower  float64 `json:"bollinger_lower"`
+
+	FiftyTwoWeekHigh float64 `json:"fifty_two_week_high"`
+	FiftyTwoWeekLow  float64 `json:"fifty_two_week_low"`
+
+	// Signals are the concrete indicator crossings (e.g. "MACD bullish
+	// crossover", "RSI oversold at 28") computed from the series above.
+	Signals []string `json:"signals"`
 }
 
 var (
 	apiFailureCount = 0
 	lastApiCallTime time.Time
-	aiService       *AIService
-	tfModelService  *TFModelService
+	aiService          *AIService
+	tfModelService     *TFModelService
+	factorModelService *FactorModelService
+	alpacaProvider     *AlpacaProvider
+	providerRegistry   *ProviderRegistry
+	finnhubStreamer    *FinnhubStreamer
 )
 
-// Cache system to reduce API calls
-var stockCache = make(map[string]*CachedStock)
+// cacheTTL is how long a cached quote is considered fresh before it must be
+// re-fetched.
+const cacheTTL = 5 * time.Minute
+
+// cacheCapacity bounds how many tickers the quote cache holds at once,
+// evicting the least-recently-used entry once it's full.
+const cacheCapacity = 1000
 
+// quoteCache replaces the old unsynchronized stockCache map: it's a
+// thread-safe, TTL-evicting LRU that also coalesces concurrent lookups for
+// the same ticker into a single upstream fetch.
+var quoteCache = cache.New(cacheCapacity, cacheTTL)
+
+// CachedStock is the value stored in quoteCache: a quote plus the time it
+// was fetched, used to compute DataAge on read.
 type CachedStock struct {
 	Data      *StockInfo
 	Timestamp time.Time
 }
 
+// cacheGet returns the cached quote for ticker if present and unexpired,
+// with DataAge updated to reflect how long it's been sitting in the cache.
+func cacheGet(ticker string) (*StockInfo, bool) {
+	v, ok := quoteCache.Get(ticker)
+	if !ok {
+		return nil, false
+	}
+	entry := v.(*CachedStock)
+	entry.Data.DataAge = int64(time.Since(entry.Timestamp).Seconds())
+	return entry.Data, true
+}
+
+// cacheSet stores info in quoteCache under ticker, timestamped now.
+func cacheSet(ticker string, info *StockInfo) {
+	quoteCache.Set(ticker, &CachedStock{Data: info, Timestamp: time.Now()})
+}
+
+// CacheStats returns the quote cache's cumulative hit/miss/eviction counts
+// for the /api/metrics endpoint.
+func CacheStats() cache.Stats {
+	return quoteCache.Stats()
+}
+
 // Common ticker mappings for popular companies
 var companyNameToTicker = map[string]string{
 	"GOOGLE":                   "GOOGL",
@@ -115,15 +221,45 @@ var companyNameToTicker = map[string]string{
 }
 
 func init() {
-	// Initialize AI service with OpenAI key from environment
-	openAIKey := os.Getenv("OPENAI_API_KEY")
-	aiService = NewAIService(openAIKey)
+	// Initialize AI service with the env-configured provider chain (see
+	// AI_PROVIDER_ORDER in null_ai_provider.go)
+	aiService = NewAIService(buildDefaultAIProviders())
 
 	// Initialize TensorFlow model service
 	tfModelService = NewTFModelService()
 
+	// Initialize the multi-factor regression model used by
+	// GetRuleBasedRecommendation's continuous-score fallback
+	factorModelService = NewFactorModelService()
+
+	// Initialize Alpaca provider if credentials are configured
+	if provider, err := NewAlpacaProvider(); err == nil {
+		alpacaProvider = provider
+		log.Println("Alpaca provider configured, will be tried before other sources")
+	} else {
+		log.Printf("Alpaca provider not configured: %v", err)
+	}
+
+	// Build the provider registry now that alpacaProvider is known
+	providerRegistry = buildDefaultRegistry()
+
+	// Initialize the Finnhub streamer if a real (non-demo) API key is
+	// configured.
+	if streamer, err := NewFinnhubStreamer(); err == nil {
+		finnhubStreamer = streamer
+		log.Println("Finnhub streamer configured, live quote streaming available")
+	} else {
+		log.Printf("Finnhub streamer not configured: %v", err)
+	}
+
 	// Log service initialization
-	log.Println("Stock services initialized. OpenAI API key present:", openAIKey != "")
+	log.Println("Stock services initialized. AI providers configured:", len(aiService.providers)-1)
+}
+
+// GetAlpacaProvider returns the shared Alpaca provider, or nil if it was not
+// configured via ALPACA_KEY_ID/ALPACA_SECRET_KEY.
+func GetAlpacaProvider() *AlpacaProvider {
+	return alpacaProvider
 }
 
 // SearchStock searches for a stock by company name or ticker
@@ -139,14 +275,16 @@ func SearchStock(query string) (*StockInfo, error) {
 	}
 
 	// Check cache first
-	if cached, ok := stockCache[ticker]; ok {
-		// If cache is less than 5 minutes old, use it
-		if time.Since(cached.Timestamp) < 5*time.Minute {
-			log.Printf("Using cached data for %s (age: %v)", ticker, time.Since(cached.Timestamp))
-			cached.Data.DataAge = int64(time.Since(cached.Timestamp).Seconds())
-			return cached.Data, nil
-		}
-		log.Printf("Cached data for %s expired, fetching fresh data", ticker)
+	if cached, ok := cacheGet(ticker); ok {
+		log.Printf("Using cached data for %s (age: %ds)", ticker, cached.DataAge)
+		return cached, nil
+	}
+
+	// Try the registered QuoteProvider fallback chain (Twelve Data, Alpha
+	// Vantage, Finnhub, Yahoo Finance) before falling back to the provider
+	// registry and demo data in FetchStockInfo.
+	if info, err := fetchFromQuoteProviders(ticker); err == nil && info != nil {
+		return addAIAnalysis(info)
 	}
 
 	return FetchStockInfo(ticker)
@@ -161,31 +299,28 @@ func FetchStockInfo(ticker string) (*StockInfo, error) {
 
 	log.Printf("Fetching stock data for ticker: %s", ticker)
 
-	// Try multiple API sources in order of preference
-	stockInfo, err := fetchFromTwelveData(ticker)
-	if err == nil && stockInfo != nil {
-		log.Printf("Successfully fetched %s data from Twelve Data", ticker)
-		return addAIAnalysis(stockInfo)
+	// Iterate registered providers in priority order, skipping any whose
+	// circuit breaker is open; the demo provider is always last and never
+	// fails, so this only returns an error if the registry itself is unset.
+	if providerRegistry != nil {
+		stockInfo, err := providerRegistry.Quote(ticker)
+		if err == nil && stockInfo != nil {
+			return addAIAnalysis(stockInfo)
+		}
+		log.Printf("All providers failed for %s: %v", ticker, err)
 	}
-	log.Printf("Twelve Data failed for %s: %v", ticker, err)
 
-	stockInfo, err = fetchFromAlphaVantage(ticker)
-	if err == nil && stockInfo != nil {
-		log.Printf("Successfully fetched %s data from Alpha Vantage", ticker)
-		return addAIAnalysis(stockInfo)
-	}
-	log.Printf("Alpha Vantage failed for %s: %v", ticker, err)
+	// If the registry isn't available yet, fall back to demo data directly.
+	return createRealisticStockData(ticker)
+}
 
-	stockInfo, err = fetchFromFinnhub(ticker)
-	if err == nil && stockInfo != nil {
-		log.Printf("Successfully fetched %s data from Finnhub", ticker)
-		return addAIAnalysis(stockInfo)
+// ProvidersHealth returns a snapshot of every registered provider's circuit
+// breaker state for the /api/providers/health endpoint.
+func ProvidersHealth() []ProviderHealthSnapshot {
+	if providerRegistry == nil {
+		return nil
 	}
-	log.Printf("Finnhub failed for %s: %v", ticker, err)
-
-	// If all APIs fail, use enhanced demo data
-	log.Printf("All APIs failed for %s, using enhanced demo data", ticker)
-	return createRealisticStockData(ticker)
+	return providerRegistry.Health()
 }
 
 // fetchFromTwelveData fetches stock data from Twelve Data (free tier allows 800 requests/day)
@@ -419,14 +554,26 @@ func parseFinnhubData(quote struct {
 }
 
 func addAIAnalysis(stockInfo *StockInfo) (*StockInfo, error) {
-	// Get AI recommendation from OpenAI
-	recommendation, err := aiService.GetStockRecommendation(stockInfo)
+	// Attach technical indicators computed from historical bars first, so
+	// the recommendation below (and the ML predictor further down) reason
+	// about real signals instead of only the current OHLC snapshot.
+	if technical, err := computeTechnicalIndicators(stockInfo.Ticker); err == nil {
+		stockInfo.Technical = technical
+	} else {
+		log.Printf("Technical indicators unavailable for %s: %v", stockInfo.Ticker, err)
+	}
+
+	// Get the structured recommendation and derive the legacy free-form
+	// Recommendation field from it, rather than making two separate
+	// provider calls for the same verdict.
+	structured, err := aiService.GetStructuredRecommendation(stockInfo)
 	if err != nil {
 		log.Printf("Failed to get AI recommendation: %v", err)
 		stockInfo.Recommendation = "HOLD - Unable to generate recommendation"
 	} else {
-		log.Printf("AI recommendation for %s: %s", stockInfo.Ticker, recommendation)
-		stockInfo.Recommendation = recommendation
+		stockInfo.StructuredRecommendation = structured
+		stockInfo.Recommendation = fmt.Sprintf("%s - %s", structured.Action, structured.Rationale)
+		log.Printf("AI recommendation for %s: %s", stockInfo.Ticker, stockInfo.Recommendation)
 	}
 
 	// Get TensorFlow predictions
@@ -437,6 +584,9 @@ func addAIAnalysis(stockInfo *StockInfo) (*StockInfo, error) {
 			stockInfo.PredictionConfidence = prediction.Confidence * 100
 			stockInfo.TrendDirection = prediction.Direction
 			stockInfo.KeyFactors = prediction.Factors
+			stockInfo.StopLoss = prediction.StopLoss
+			stockInfo.TakeProfit = prediction.TakeProfit
+			stockInfo.TrailingCallbackRate = prediction.TrailingCallbackRate
 
 			// Generate AI analysis based on TF prediction
 			priceDiff := ((prediction.PredictedPrice - stockInfo.Price) / stockInfo.Price) * 100
@@ -463,12 +613,18 @@ func addAIAnalysis(stockInfo *StockInfo) (*StockInfo, error) {
 		}
 	}
 
-	// Cache the results
-	stockCache[stockInfo.Ticker] = &CachedStock{
-		Data:      stockInfo,
-		Timestamp: time.Now(),
+	// Surface the multi-factor regression's per-factor coefficients
+	// alongside the ML prediction's factors, once enough history has
+	// accumulated to fit it.
+	if factorModelService != nil {
+		if score, err := factorModelService.Score(stockInfo); err == nil {
+			stockInfo.KeyFactors = append(stockInfo.KeyFactors, score.FactorExplanations()...)
+		}
 	}
 
+	// Cache the results
+	cacheSet(stockInfo.Ticker, stockInfo)
+
 	return stockInfo, nil
 }
 
@@ -510,6 +666,32 @@ func formatMarketCap(marketCap int64) string {
 	return fmt.Sprintf("$%d", marketCap)
 }
 
+// parseMarketCapValue reverses formatMarketCap, parsing strings like
+// "$2.5T"/"$150B"/"$800M" back into a raw dollar value. Returns 0 for
+// unparsable or non-"$"-prefixed input (e.g. "N/A").
+func parseMarketCapValue(marketCap string) float64 {
+	if !strings.HasPrefix(marketCap, "$") {
+		return 0
+	}
+	mcStr := strings.TrimPrefix(marketCap, "$")
+
+	switch {
+	case strings.HasSuffix(mcStr, "T"):
+		if val, err := strconv.ParseFloat(strings.TrimSuffix(mcStr, "T"), 64); err == nil {
+			return val * 1e12
+		}
+	case strings.HasSuffix(mcStr, "B"):
+		if val, err := strconv.ParseFloat(strings.TrimSuffix(mcStr, "B"), 64); err == nil {
+			return val * 1e9
+		}
+	case strings.HasSuffix(mcStr, "M"):
+		if val, err := strconv.ParseFloat(strings.TrimSuffix(mcStr, "M"), 64); err == nil {
+			return val * 1e6
+		}
+	}
+	return 0
+}
+
 // createRealisticStockData creates realistic demo data when APIs are unavailable
 func createRealisticStockData(ticker string) (*StockInfo, error) {
 	log.Printf("Creating realistic demo data for ticker: %s", ticker)
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"log"
+	"strings"
+)
+
+// maxBatchSize caps how many tickers a single batch request can request at
+// once, matching the limit enforced by the /api/stocks/batch handler.
+const maxBatchSize = 100
+
+// FetchStockInfoBatch resolves StockInfo for a set of tickers in as few
+// round-trips as possible: de-duplicated, cache-hits are served first, and
+// the remaining symbols are fetched through the provider registry (which
+// groups them into a single bulk call where the provider supports it, e.g.
+// Alpaca's snapshots endpoint). Results are written back to the cache in one
+// pass under a single lock acquisition.
+func FetchStockInfoBatch(tickers []string) map[string]*StockInfo {
+	results := make(map[string]*StockInfo)
+	var missing []string
+
+	seen := make(map[string]bool)
+	for _, raw := range tickers {
+		ticker := strings.ToUpper(strings.TrimSpace(raw))
+		if ticker == "" || seen[ticker] {
+			continue
+		}
+		seen[ticker] = true
+
+		if cached := cachedStockIfFresh(ticker); cached != nil {
+			results[ticker] = cached
+			continue
+		}
+		missing = append(missing, ticker)
+	}
+
+	if len(missing) == 0 {
+		return results
+	}
+
+	fetched := fetchBulk(missing)
+
+	for ticker, info := range fetched {
+		cacheSet(ticker, info)
+		results[ticker] = info
+	}
+
+	return results
+}
+
+// cachedStockIfFresh returns the cached entry for ticker if it exists and is
+// still within cacheTTL.
+func cachedStockIfFresh(ticker string) *StockInfo {
+	cached, ok := cacheGet(ticker)
+	if !ok {
+		return nil
+	}
+	return cached
+}
+
+// fetchBulk fetches tickers not already in cache, preferring a provider's
+// bulk endpoint (e.g. Alpaca snapshots, which accepts a comma-separated
+// symbol list) and falling back to per-ticker fetches for providers that
+// don't support batching.
+func fetchBulk(tickers []string) map[string]*StockInfo {
+	fetched := make(map[string]*StockInfo)
+
+	if alpacaProvider != nil {
+		bulk, remaining := alpacaProvider.Snapshots(tickers)
+		for ticker, info := range bulk {
+			fetched[ticker] = info
+		}
+		tickers = remaining
+	}
+
+	for _, ticker := range tickers {
+		info, err := FetchStockInfo(ticker)
+		if err != nil {
+			log.Printf("Batch fetch failed for %s: %v", ticker, err)
+			continue
+		}
+		fetched[ticker] = info
+	}
+
+	return fetched
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+)
+
+// TestFetchStocksBatchPreservesOrderAndDedup seeds the cache so the batch
+// resolves without any network access, then checks the result is ordered
+// the way the caller asked (not cache-iteration order) and that repeated
+// tickers in the input collapse to a single entry.
+func TestFetchStocksBatchPreservesOrderAndDedup(t *testing.T) {
+	tickers := []string{"BATCHC", "BATCHA", "BATCHB"}
+	for i, ticker := range tickers {
+		CacheStockInfo(ticker, &StockInfo{
+			Ticker: ticker,
+			Price:  float64(100 + i),
+		})
+	}
+
+	results, err := FetchStocksBatch([]string{"BATCHC", "BATCHA", "BATCHC", "batcha", "BATCHB"})
+	if err != nil {
+		t.Fatalf("FetchStocksBatch failed: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 deduplicated tickers, got %d: %+v", len(results), results)
+	}
+
+	want := []string{"BATCHC", "BATCHA", "BATCHB"}
+	for i, info := range results {
+		if info.Ticker != want[i] {
+			t.Errorf("result[%d] = %s, want %s (order not preserved)", i, info.Ticker, want[i])
+		}
+	}
+}
+
+// TestFetchStocksBatchKeepsTickerWithoutPrediction verifies a ticker stays
+// in the batch result even when PredictStockMovement has nothing to offer
+// for it (e.g. no TF model configured in this environment), matching the
+// request's requirement that a failed prediction must not drop the ticker.
+func TestFetchStocksBatchKeepsTickerWithoutPrediction(t *testing.T) {
+	CacheStockInfo("BATCHNOPRED", &StockInfo{Ticker: "BATCHNOPRED", Price: 42})
+
+	results, err := FetchStocksBatch([]string{"BATCHNOPRED"})
+	if err != nil {
+		t.Fatalf("FetchStocksBatch failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Ticker != "BATCHNOPRED" {
+		t.Fatalf("expected BATCHNOPRED to remain in the batch, got %+v", results)
+	}
+	if results[0].Recommendation == "" {
+		t.Errorf("expected GetRuleBasedRecommendation to still populate a recommendation")
+	}
+}
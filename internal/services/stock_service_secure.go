@@ -11,7 +11,6 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 )
 
 // sanitizeSearchQuery sanitizes and validates the search input
@@ -46,15 +45,10 @@ func SearchStockSecure(query string) (*StockInfo, error) {
 	}
 	
 	// Check cache first
-	if cached, ok := stockCache[ticker]; ok {
-		// If cache is less than 5 minutes old, use it
-		if time.Since(cached.Timestamp) < 5*time.Minute {
-			log.Printf("Using cached data for %s (age: %v)", ticker, time.Since(cached.Timestamp))
-			cached.Data.DataAge = int64(time.Since(cached.Timestamp).Seconds())
-			return cached.Data, nil
-		}
-		log.Printf("Cached data for %s expired, fetching fresh data", ticker)
+	if cached, ok := cacheGet(ticker); ok {
+		log.Printf("Using cached data for %s (age: %ds)", ticker, cached.DataAge)
+		return cached, nil
 	}
-	
+
 	return FetchStockInfo(ticker)
 }
@@ -98,24 +98,14 @@ func TestCaching(t *testing.T) {
 		DataAge:     0,
 	}
 
-	// Clear the cache
-	stockCache = make(map[string]*CachedStock)
-	
 	// Manually add entry to cache
 	CacheStockInfo("TEST", testStock)
-	
+
 	// Verify cache is populated
-	if _, ok := stockCache["TEST"]; !ok {
+	if cached := GetCachedStock("TEST"); cached == nil {
 		t.Errorf("Cache was not populated with TEST data")
 	}
-	
-	// Check data age is set
-	if cached, ok := stockCache["TEST"]; ok {
-		if cached.Data.DataAge > 0 {
-			t.Errorf("Initial data age should be 0 seconds, got %d", cached.Data.DataAge)
-		}
-	}
-	
+
 	// Sleep a bit to ensure time passes
 	time.Sleep(10 * time.Millisecond)
 	
@@ -0,0 +1,176 @@
+// Package stream maintains live, per-symbol quote subscriptions for the
+// /ws/quotes WebSocket: a subscription hub keyed by symbol, with a single
+// background poller per symbol that starts on its first subscriber and
+// stops once the last one leaves.
+package stream
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/martinrizk/investify/internal/services"
+)
+
+// DefaultPollInterval is how often each per-symbol poller fetches a fresh
+// quote when NewHub is given a zero interval.
+const DefaultPollInterval = 5 * time.Second
+
+// ClientSendBuffer is the suggested size for a Client's own outbound
+// buffer; Hub doesn't enforce it, but handlers.streamClient uses it so the
+// drop policy lives at the same layer as the websocket connection.
+const ClientSendBuffer = 32
+
+// Tick is one push to a subscribed client: a fresh quote plus the ML
+// prediction computed from the same snapshot.
+type Tick struct {
+	Stock      *services.StockInfo       `json:"stock"`
+	Prediction *services.StockPrediction `json:"prediction,omitempty"`
+}
+
+// Client is anything that can receive Ticks and be dropped if it falls
+// behind; Send must not block. The handlers package's websocket client
+// satisfies this with a buffered channel that discards on overflow.
+type Client interface {
+	Send(Tick)
+}
+
+// Hub tracks, per symbol, the set of subscribed clients and the single
+// background poller feeding them.
+type Hub struct {
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	clients map[string]map[Client]struct{}
+	cancel  map[string]context.CancelFunc
+}
+
+// NewHub creates a Hub whose pollers fetch every interval, or
+// DefaultPollInterval if interval is zero or negative.
+func NewHub(interval time.Duration) *Hub {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &Hub{
+		pollInterval: interval,
+		clients:      make(map[string]map[Client]struct{}),
+		cancel:       make(map[string]context.CancelFunc),
+	}
+}
+
+// Subscribe adds c as a subscriber to symbol, starting symbol's poller if
+// this is its first subscriber.
+func (h *Hub) Subscribe(c Client, symbol string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[symbol] == nil {
+		h.clients[symbol] = make(map[Client]struct{})
+	}
+	h.clients[symbol][c] = struct{}{}
+
+	if _, running := h.cancel[symbol]; !running {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.cancel[symbol] = cancel
+		go h.poll(ctx, symbol)
+	}
+}
+
+// Unsubscribe removes c from symbol, stopping symbol's poller if c was the
+// last subscriber.
+func (h *Hub) Unsubscribe(c Client, symbol string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(c, symbol)
+}
+
+// RemoveClient unsubscribes c from every symbol it holds, e.g. once its
+// connection closes.
+func (h *Hub) RemoveClient(c Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for symbol, set := range h.clients {
+		if _, ok := set[c]; ok {
+			h.removeLocked(c, symbol)
+		}
+	}
+}
+
+// Shutdown stops every running poller, for use when the process is
+// shutting down gracefully.
+func (h *Hub) Shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for symbol, cancel := range h.cancel {
+		cancel()
+		delete(h.cancel, symbol)
+	}
+	h.clients = make(map[string]map[Client]struct{})
+}
+
+// removeLocked removes c from symbol's subscriber set and stops symbol's
+// poller if that was the last subscriber. Callers must hold h.mu.
+func (h *Hub) removeLocked(c Client, symbol string) {
+	set := h.clients[symbol]
+	if set == nil {
+		return
+	}
+	delete(set, c)
+	if len(set) > 0 {
+		return
+	}
+
+	delete(h.clients, symbol)
+	if cancel, ok := h.cancel[symbol]; ok {
+		cancel()
+		delete(h.cancel, symbol)
+	}
+}
+
+// poll fetches symbol's quote and prediction every h.pollInterval and fans
+// them out to every current subscriber, exiting once ctx is cancelled
+// (i.e. the last subscriber left).
+func (h *Hub) poll(ctx context.Context, symbol string) {
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.fetchAndBroadcast(symbol)
+		}
+	}
+}
+
+// fetchAndBroadcast fetches one fresh Tick for symbol and sends it to
+// every current subscriber.
+func (h *Hub) fetchAndBroadcast(symbol string) {
+	stock, err := services.FetchStockInfo(symbol)
+	if err != nil {
+		log.Printf("stream: failed to fetch %s: %v", symbol, err)
+		return
+	}
+
+	prediction, err := services.PredictStockMovement(stock)
+	if err != nil {
+		prediction = nil
+	}
+
+	tick := Tick{Stock: stock, Prediction: prediction}
+
+	h.mu.Lock()
+	subscribers := make([]Client, 0, len(h.clients[symbol]))
+	for c := range h.clients[symbol] {
+		subscribers = append(subscribers, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range subscribers {
+		c.Send(tick)
+	}
+}
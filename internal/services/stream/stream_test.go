@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClient records every Tick it receives, for assertions on delivery
+// without needing a real websocket connection.
+type fakeClient struct {
+	mu    sync.Mutex
+	ticks []Tick
+}
+
+func (c *fakeClient) Send(t Tick) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ticks = append(c.ticks, t)
+}
+
+func (c *fakeClient) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.ticks)
+}
+
+// TestHubDeliversTicksToSubscriber verifies a subscribed client starts
+// receiving ticks for its symbol.
+func TestHubDeliversTicksToSubscriber(t *testing.T) {
+	h := NewHub(20 * time.Millisecond)
+	c := &fakeClient{}
+
+	h.Subscribe(c, "AAPL")
+	defer h.Unsubscribe(c, "AAPL")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for c.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.count() == 0 {
+		t.Fatal("expected at least one tick to be delivered to the subscriber")
+	}
+}
+
+// TestHubStopsPollerAfterLastUnsubscribe verifies a symbol's poller stops
+// producing ticks once its last subscriber leaves.
+func TestHubStopsPollerAfterLastUnsubscribe(t *testing.T) {
+	h := NewHub(20 * time.Millisecond)
+	c := &fakeClient{}
+
+	h.Subscribe(c, "MSFT")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for c.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.count() == 0 {
+		t.Fatal("expected at least one tick before unsubscribing")
+	}
+
+	h.Unsubscribe(c, "MSFT")
+	after := c.count()
+	time.Sleep(100 * time.Millisecond)
+	if c.count() != after {
+		t.Errorf("expected no further ticks after the last subscriber left, got %d more", c.count()-after)
+	}
+
+	h.mu.Lock()
+	_, running := h.cancel["MSFT"]
+	h.mu.Unlock()
+	if running {
+		t.Error("expected MSFT's poller to be stopped after the last unsubscribe")
+	}
+}
+
+// TestHubRemoveClientUnsubscribesAll verifies RemoveClient tears down every
+// symbol a client was subscribed to.
+func TestHubRemoveClientUnsubscribesAll(t *testing.T) {
+	h := NewHub(20 * time.Millisecond)
+	c := &fakeClient{}
+
+	h.Subscribe(c, "TSLA")
+	h.Subscribe(c, "GOOGL")
+	h.RemoveClient(c)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.clients) != 0 || len(h.cancel) != 0 {
+		t.Errorf("expected RemoveClient to clear all subscriptions, got clients=%v cancel=%v", h.clients, h.cancel)
+	}
+}
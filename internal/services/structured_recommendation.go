@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// Action is a Recommendation's directional verdict.
+type Action string
+
+const (
+	ActionBuy        Action = "BUY"
+	ActionSell       Action = "SELL"
+	ActionHold       Action = "HOLD"
+	ActionBuyWeak    Action = "BUY_WEAK"
+	ActionSellWeak   Action = "SELL_WEAK"
+	ActionBuyStrong  Action = "BUY_STRONG"
+	ActionSellStrong Action = "SELL_STRONG"
+)
+
+// valid reports whether a is one of the enumerated Action values.
+func (a Action) valid() bool {
+	switch a {
+	case ActionBuy, ActionSell, ActionHold, ActionBuyWeak, ActionSellWeak, ActionBuyStrong, ActionSellStrong:
+		return true
+	}
+	return false
+}
+
+// SignalContribution is one factor behind a Recommendation's Confidence,
+// e.g. {"trend", 0.4} from scoreTechnicals' weighted components.
+type SignalContribution struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// Recommendation is the structured form of a stock verdict: the same
+// information GetStockRecommendation's free-form string carries, broken
+// into fields a client can render directly (a confidence bar, target/stop
+// lines on a chart, a per-signal breakdown) instead of parsing prose.
+type Recommendation struct {
+	Ticker      string               `json:"ticker"`
+	Action      Action               `json:"action"`
+	Confidence  float64              `json:"confidence"`
+	TargetPrice float64              `json:"target_price"`
+	StopLoss    float64              `json:"stop_loss"`
+	TimeHorizon string               `json:"time_horizon"`
+	Rationale   string               `json:"rationale"`
+	Signals     []SignalContribution `json:"signals"`
+	Provider    string               `json:"provider"`
+	GeneratedAt time.Time            `json:"generated_at"`
+}
+
+// jsonModeProvider is implemented by providers that can be asked to
+// constrain their output to JSON at the API level (currently
+// OpenAIProvider's response_format: json_object) rather than relying
+// solely on structuredRecommendationSchemaPrompt's instructions.
+type jsonModeProvider interface {
+	CompleteJSON(ctx context.Context, systemPrompt, userPrompt string, maxTokens int) (string, error)
+}
+
+// structuredCompletionMaxTokens is larger than aiCompletionMaxTokens since
+// the JSON envelope (field names, the signals array) costs tokens beyond
+// the rationale text itself.
+const structuredCompletionMaxTokens = 300
+
+// structuredRecommendationSchemaPrompt is appended to the normal
+// recommendation prompt so a provider without native JSON-mode still has
+// the exact field names and allowed Action values to follow. OpenAIProvider
+// additionally sets response_format to json_object (see openai_provider.go)
+// so OpenAI enforces this at the API level rather than relying on the
+// model to follow instructions.
+const structuredRecommendationSchemaPrompt = `Respond with ONLY a single JSON object (no prose, no markdown code fences) matching exactly this schema:
+{
+  "action": one of "BUY", "SELL", "HOLD", "BUY_WEAK", "SELL_WEAK",
+  "confidence": number between 0 and 1,
+  "target_price": number,
+  "stop_loss": number,
+  "time_horizon": string, e.g. "1-3 days" or "2-4 weeks",
+  "rationale": string,
+  "signals": [{"name": string, "weight": number}, ...]
+}`
+
+// GetStructuredRecommendation is GetStockRecommendation's structured
+// counterpart: it asks each configured provider in turn for a
+// Recommendation as JSON, validating (and, for Confidence, repairing) the
+// response before accepting it, and falls through to the next provider on
+// a request error or a malformed response the same way GetStockRecommendation
+// falls through on a request error alone. The rule-based fallback can't
+// produce malformed output, so this never returns an error.
+func (ai *AIService) GetStructuredRecommendation(stock *StockInfo) (*Recommendation, error) {
+	systemPrompt := "You are a financial advisor producing machine-readable stock recommendations."
+	userPrompt := stockRecommendationPrompt(stock) + "\n\n" + structuredRecommendationSchemaPrompt
+
+	for _, provider := range ai.providers {
+		if _, isNull := provider.(NullProvider); isNull {
+			log.Printf("Structured AI recommendation answered by provider %q", provider.Name())
+			return ai.ruleBasedStructuredRecommendation(stock), nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), aiRequestTimeout)
+		var raw string
+		var err error
+		if jsonProvider, ok := provider.(jsonModeProvider); ok {
+			raw, err = jsonProvider.CompleteJSON(ctx, systemPrompt, userPrompt, structuredCompletionMaxTokens)
+		} else {
+			raw, err = provider.Complete(ctx, systemPrompt, userPrompt, structuredCompletionMaxTokens)
+		}
+		cancel()
+		if err != nil {
+			log.Printf("AI provider %q failed, falling through: %v", provider.Name(), err)
+			continue
+		}
+
+		rec, err := parseStructuredRecommendation(raw)
+		if err != nil {
+			log.Printf("AI provider %q returned a malformed structured recommendation, falling through: %v", provider.Name(), err)
+			continue
+		}
+
+		rec.Ticker = stock.Ticker
+		rec.Provider = provider.Name()
+		rec.GeneratedAt = time.Now()
+		log.Printf("Structured AI recommendation answered by provider %q", provider.Name())
+		return rec, nil
+	}
+
+	return ai.ruleBasedStructuredRecommendation(stock), nil
+}
+
+// parseStructuredRecommendation decodes raw as a Recommendation and
+// validates it, returning an error if it's malformed in a way that can't
+// be repaired (an Action outside the enum).
+func parseStructuredRecommendation(raw string) (*Recommendation, error) {
+	raw = stripJSONFences(raw)
+
+	var rec Recommendation
+	if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+		return nil, fmt.Errorf("structured recommendation: invalid JSON: %w", err)
+	}
+	if !rec.Action.valid() {
+		return nil, fmt.Errorf("structured recommendation: invalid action %q", rec.Action)
+	}
+
+	// Confidence is the one field worth repairing rather than rejecting
+	// outright: a provider reporting 1.2 or -0.1 almost always still means
+	// "very confident" or "not confident", just outside the documented
+	// range.
+	rec.Confidence = math.Max(0, math.Min(1, rec.Confidence))
+
+	return &rec, nil
+}
+
+// stripJSONFences removes a leading/trailing ```json fenced block, in case
+// a provider wraps its JSON in markdown despite
+// structuredRecommendationSchemaPrompt asking it not to.
+func stripJSONFences(raw string) string {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSuffix(raw, "```")
+	return strings.TrimSpace(raw)
+}
+
+// ruleBasedStructuredRecommendation builds a Recommendation directly from
+// GetRuleBasedRecommendation and, when available, scoreTechnicals' weighted
+// breakdown - the same inputs the rule-based engine always had, just
+// returned as data instead of a formatted string.
+func (ai *AIService) ruleBasedStructuredRecommendation(stock *StockInfo) *Recommendation {
+	rule := GetRuleBasedRecommendation(stock)
+	confidence, signals := ruleBasedConfidenceAndSignals(stock)
+
+	return &Recommendation{
+		Ticker:      stock.Ticker,
+		Action:      actionFromRuleText(rule.Text),
+		Confidence:  confidence,
+		TargetPrice: rule.TakeProfit,
+		StopLoss:    rule.StopLoss,
+		TimeHorizon: "short-term",
+		Rationale:   rule.Text,
+		Signals:     signals,
+		Provider:    "rule-based",
+		GeneratedAt: time.Now(),
+	}
+}
+
+// ruleBasedConfidenceAndSignals derives a Confidence and the
+// SignalContributions behind it from stock.Technical's weighted score,
+// falling back to a single coarse signal when there isn't enough history
+// to have computed one.
+func ruleBasedConfidenceAndSignals(stock *StockInfo) (float64, []SignalContribution) {
+	if stock.Technical == nil {
+		return 0.5, []SignalContribution{{Name: "day-range position", Weight: 0.5}}
+	}
+
+	score := scoreTechnicals(stock.Technical, stock.Price)
+	confidence := math.Min(1, math.Abs(score.composite()))
+	signals := []SignalContribution{
+		{Name: "trend", Weight: score.trend * trendWeight},
+		{Name: "momentum", Weight: score.momentum * momentumWeight},
+		{Name: "mean-reversion", Weight: score.meanReversion * meanReversionWeight},
+	}
+	return confidence, signals
+}
+
+// actionFromRuleText maps GetRuleBasedRecommendation's "VERDICT - reason"
+// text onto Action, including the legacy "HOLD/BUY" verdict the day-range
+// fallback still emits as a weak buy signal, and the "STRONG BUY"/"STRONG
+// SELL" verdicts FactorScore.Recommendation prefers for its
+// highest-conviction signals.
+func actionFromRuleText(text string) Action {
+	verdict := text
+	if idx := strings.Index(text, " - "); idx >= 0 {
+		verdict = text[:idx]
+	}
+
+	switch verdict {
+	case "STRONG BUY":
+		return ActionBuyStrong
+	case "BUY":
+		return ActionBuy
+	case "STRONG SELL":
+		return ActionSellStrong
+	case "SELL":
+		return ActionSell
+	case "HOLD/BUY":
+		return ActionBuyWeak
+	default:
+		return ActionHold
+	}
+}
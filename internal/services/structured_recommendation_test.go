@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+// jsonProvider always answers with a fixed JSON body, for exercising
+// GetStructuredRecommendation's happy path and its jsonModeProvider
+// type-assertion.
+type jsonProvider struct {
+	name string
+	body string
+}
+
+func (p jsonProvider) Name() string { return p.name }
+
+func (p jsonProvider) Complete(ctx context.Context, systemPrompt, userPrompt string, maxTokens int) (string, error) {
+	return p.body, nil
+}
+
+func TestGetStructuredRecommendationParsesValidJSON(t *testing.T) {
+	provider := jsonProvider{name: "stub", body: `{
+		"action": "BUY",
+		"confidence": 0.8,
+		"target_price": 160,
+		"stop_loss": 140,
+		"time_horizon": "1-3 days",
+		"rationale": "Strong momentum",
+		"signals": [{"name": "momentum", "weight": 0.6}]
+	}`}
+
+	aiService := NewAIService([]Provider{provider, NullProvider{}})
+	stock := &StockInfo{Ticker: "AAPL", Price: 150}
+
+	rec, err := aiService.GetStructuredRecommendation(stock)
+	if err != nil {
+		t.Fatalf("GetStructuredRecommendation: %v", err)
+	}
+	if rec.Action != ActionBuy {
+		t.Errorf("Action = %q, want %q", rec.Action, ActionBuy)
+	}
+	if rec.Provider != "stub" {
+		t.Errorf("Provider = %q, want %q", rec.Provider, "stub")
+	}
+	if rec.Ticker != "AAPL" {
+		t.Errorf("Ticker = %q, want %q", rec.Ticker, "AAPL")
+	}
+}
+
+func TestGetStructuredRecommendationFallsThroughOnMalformedJSON(t *testing.T) {
+	provider := jsonProvider{name: "stub", body: "not json"}
+
+	aiService := NewAIService([]Provider{provider, NullProvider{}})
+	stock := &StockInfo{Ticker: "AAPL", Price: 150, High: 152, Low: 148}
+
+	rec, err := aiService.GetStructuredRecommendation(stock)
+	if err != nil {
+		t.Fatalf("expected the rule-based fallback to answer without error, got: %v", err)
+	}
+	if rec.Provider != "rule-based" {
+		t.Errorf("Provider = %q, want %q after a malformed provider response", rec.Provider, "rule-based")
+	}
+}
+
+func TestGetStructuredRecommendationFallsThroughOnInvalidAction(t *testing.T) {
+	provider := jsonProvider{name: "stub", body: `{"action": "MAYBE", "confidence": 0.5, "rationale": "unclear"}`}
+
+	aiService := NewAIService([]Provider{provider, NullProvider{}})
+	stock := &StockInfo{Ticker: "AAPL", Price: 150, High: 152, Low: 148}
+
+	rec, err := aiService.GetStructuredRecommendation(stock)
+	if err != nil {
+		t.Fatalf("expected the rule-based fallback to answer without error, got: %v", err)
+	}
+	if rec.Provider != "rule-based" {
+		t.Errorf("Provider = %q, want %q after an invalid action", rec.Provider, "rule-based")
+	}
+}
+
+func TestParseStructuredRecommendationClampsConfidence(t *testing.T) {
+	rec, err := parseStructuredRecommendation(`{"action": "HOLD", "confidence": 1.5}`)
+	if err != nil {
+		t.Fatalf("parseStructuredRecommendation: %v", err)
+	}
+	if rec.Confidence != 1 {
+		t.Errorf("Confidence = %v, want clamped to 1", rec.Confidence)
+	}
+}
+
+func TestParseStructuredRecommendationStripsJSONFences(t *testing.T) {
+	rec, err := parseStructuredRecommendation("```json\n{\"action\": \"SELL\", \"confidence\": 0.4}\n```")
+	if err != nil {
+		t.Fatalf("parseStructuredRecommendation: %v", err)
+	}
+	if rec.Action != ActionSell {
+		t.Errorf("Action = %q, want %q", rec.Action, ActionSell)
+	}
+}
+
+func TestRuleBasedStructuredRecommendationUsesTechnicalSignals(t *testing.T) {
+	aiService := NewAIService([]Provider{NullProvider{}})
+	stock := &StockInfo{
+		Ticker: "AAPL",
+		Price:  105,
+		Technical: &TechnicalIndicators{
+			SMA20: 100, SMA50: 95, SMA200: 90,
+			RSI14:           25,
+			MACDHistogram:   0.5,
+			BollingerUpper:  110,
+			BollingerMiddle: 100,
+			BollingerLower:  90,
+		},
+	}
+
+	rec, err := aiService.GetStructuredRecommendation(stock)
+	if err != nil {
+		t.Fatalf("GetStructuredRecommendation: %v", err)
+	}
+	if len(rec.Signals) != 3 {
+		t.Fatalf("expected 3 signal contributions from the technical score, got %d", len(rec.Signals))
+	}
+	if rec.Action != ActionBuy {
+		t.Errorf("Action = %q, want %q for an all-bullish technical stack", rec.Action, ActionBuy)
+	}
+}
+
+func TestActionFromRuleText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Action
+	}{
+		{"strong buy", "STRONG BUY - Score 2.40, led by momentum", ActionBuyStrong},
+		{"buy", "BUY - RSI oversold at 28", ActionBuy},
+		{"strong sell", "STRONG SELL - Score -2.40, led by trend", ActionSellStrong},
+		{"sell", "SELL - RSI overbought at 73", ActionSell},
+		{"legacy weak buy", "HOLD/BUY - price near day low", ActionBuyWeak},
+		{"hold", "HOLD - no strong signal", ActionHold},
+		{"unrecognized verdict", "MAYBE - unclear", ActionHold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := actionFromRuleText(tt.text); got != tt.want {
+				t.Errorf("actionFromRuleText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
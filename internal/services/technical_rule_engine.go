@@ -0,0 +1,119 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Weights for technicalScore's three components. Trend gets the largest
+// share since a moving-average regime is the least noisy of the three;
+// momentum and mean-reversion split the rest.
+const (
+	trendWeight         = 0.40
+	momentumWeight      = 0.35
+	meanReversionWeight = 0.25
+)
+
+// technicalBuyThreshold and technicalSellThreshold bound the weighted
+// score (in [-1, 1]) that technicalRecommendation treats as decisive
+// rather than HOLD.
+const (
+	technicalBuyThreshold  = 0.3
+	technicalSellThreshold = -0.3
+)
+
+// technicalScore is technicalRecommendation's breakdown of stock.Technical
+// into the three weighted components its composite score is built from.
+type technicalScore struct {
+	trend         float64
+	momentum      float64
+	meanReversion float64
+}
+
+// composite combines trend, momentum, and meanReversion into a single
+// score in [-1, 1] using trendWeight/momentumWeight/meanReversionWeight.
+func (s technicalScore) composite() float64 {
+	return s.trend*trendWeight + s.momentum*momentumWeight + s.meanReversion*meanReversionWeight
+}
+
+// scoreTechnicals derives a technicalScore from t's moving averages, RSI,
+// MACD histogram, and Bollinger Band position relative to price.
+func scoreTechnicals(t *TechnicalIndicators, price float64) technicalScore {
+	var s technicalScore
+
+	// Trend: reward a stack of price > SMA20 > SMA50 (> SMA200, once there's
+	// a full year of history to compute it), penalize the mirror image.
+	trendUp, trendDown := 0, 0
+	if price > t.SMA20 {
+		trendUp++
+	} else if price < t.SMA20 {
+		trendDown++
+	}
+	if t.SMA20 > t.SMA50 {
+		trendUp++
+	} else if t.SMA20 < t.SMA50 {
+		trendDown++
+	}
+	if t.SMA200 > 0 {
+		if t.SMA50 > t.SMA200 {
+			trendUp++
+		} else if t.SMA50 < t.SMA200 {
+			trendDown++
+		}
+	}
+	s.trend = float64(trendUp-trendDown) / 3
+
+	// Momentum: RSI bands plus MACD histogram sign.
+	momentum := 0.0
+	switch {
+	case t.RSI14 <= 30:
+		momentum += 1
+	case t.RSI14 >= 70:
+		momentum -= 1
+	}
+	if t.MACDHistogram > 0 {
+		momentum += 1
+	} else if t.MACDHistogram < 0 {
+		momentum -= 1
+	}
+	s.momentum = momentum / 2
+
+	// Mean-reversion: a price pinned against a Bollinger Band tends to snap
+	// back, so the signal points opposite the band it's broken.
+	if bandWidth := t.BollingerUpper - t.BollingerLower; bandWidth > 0 {
+		switch {
+		case price >= t.BollingerUpper:
+			s.meanReversion = -1
+		case price <= t.BollingerLower:
+			s.meanReversion = 1
+		}
+	}
+
+	return s
+}
+
+// technicalRecommendation turns stock.Technical into a BUY/SELL/HOLD
+// verdict using scoreTechnicals' weighted composite, citing the concrete
+// signals (from indicators.Result.Signals, already attached to
+// stock.Technical by computeTechnicalIndicators) that drove it.
+func technicalRecommendation(stock *StockInfo) string {
+	t := stock.Technical
+	score := scoreTechnicals(t, stock.Price)
+	composite := score.composite()
+
+	verdict := "HOLD"
+	switch {
+	case composite >= technicalBuyThreshold:
+		verdict = "BUY"
+	case composite <= technicalSellThreshold:
+		verdict = "SELL"
+	}
+
+	reason := "Technical indicators show no strong signal"
+	if len(t.Signals) > 0 {
+		reason = strings.Join(t.Signals, "; ")
+	}
+
+	return fmt.Sprintf("%s - %s (weighted score %.2f: trend %.2f, momentum %.2f, mean-reversion %.2f)",
+		verdict, reason, composite, score.trend, score.momentum, score.meanReversion)
+}
@@ -0,0 +1,68 @@
+package services
+
+import "testing"
+
+func TestScoreTechnicalsBullishStack(t *testing.T) {
+	technical := &TechnicalIndicators{
+		SMA20: 100, SMA50: 95, SMA200: 90,
+		RSI14:           25,
+		MACDHistogram:   0.5,
+		BollingerUpper:  110,
+		BollingerMiddle: 100,
+		BollingerLower:  90,
+	}
+
+	score := scoreTechnicals(technical, 105)
+	if score.trend <= 0 {
+		t.Errorf("expected positive trend for price > SMA20 > SMA50 > SMA200, got %v", score.trend)
+	}
+	if score.momentum <= 0 {
+		t.Errorf("expected positive momentum for oversold RSI + rising MACD histogram, got %v", score.momentum)
+	}
+	if composite := score.composite(); composite < technicalBuyThreshold {
+		t.Errorf("composite score %v below BUY threshold %v for an all-bullish stack", composite, technicalBuyThreshold)
+	}
+}
+
+func TestScoreTechnicalsBearishStack(t *testing.T) {
+	technical := &TechnicalIndicators{
+		SMA20: 90, SMA50: 95, SMA200: 100,
+		RSI14:           75,
+		MACDHistogram:   -0.5,
+		BollingerUpper:  110,
+		BollingerMiddle: 100,
+		BollingerLower:  90,
+	}
+
+	score := scoreTechnicals(technical, 85)
+	if score.trend >= 0 {
+		t.Errorf("expected negative trend for price < SMA20 < SMA50 < SMA200 stack, got %v", score.trend)
+	}
+	if composite := score.composite(); composite > technicalSellThreshold {
+		t.Errorf("composite score %v above SELL threshold %v for an all-bearish stack", composite, technicalSellThreshold)
+	}
+}
+
+func TestTechnicalRecommendationCitesSignals(t *testing.T) {
+	stock := &StockInfo{
+		Ticker: "AAPL",
+		Price:  105,
+		Technical: &TechnicalIndicators{
+			SMA20: 100, SMA50: 95, SMA200: 90,
+			RSI14:           25,
+			MACDHistogram:   0.5,
+			BollingerUpper:  110,
+			BollingerMiddle: 100,
+			BollingerLower:  90,
+			Signals:         []string{"RSI oversold at 25"},
+		},
+	}
+
+	got := technicalRecommendation(stock)
+	if got == "" {
+		t.Fatal("expected a non-empty recommendation")
+	}
+	if want := "BUY"; got[:len(want)] != want {
+		t.Errorf("recommendation = %q, want it to start with %q", got, want)
+	}
+}
@@ -1,26 +1,87 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/martinrizk/investify/internal/estimator"
+	"github.com/martinrizk/investify/internal/indicators"
 )
 
 // TFModelService handles TensorFlow-based stock predictions
 type TFModelService struct {
 	modelReady bool
+
+	// Window sizes for the indicators simulatePrediction derives from each
+	// ticker's recent-snapshot history, overridable via
+	// PREDICTION_ATR_WINDOW, PREDICTION_FISHER_WINDOW, and
+	// PREDICTION_DRIFT_WINDOW so the same tuning knobs the drift strategy
+	// docs describe in YAML can be set here through the environment.
+	atrWindow             int
+	fisherTransformWindow int
+	smootherWindow        int
+
+	// estimatorName selects the PriceEstimator (see internal/estimator)
+	// predictWithEstimator fits on each ticker's lag-feature history,
+	// overridable via PREDICTION_ESTIMATOR ("ols", "knn", "gbdt", or
+	// "python" for the existing Python bridge).
+	estimatorName string
+
+	// Bracket-order risk parameters attachRiskLevels derives StopLoss/
+	// TakeProfit/TrailingCallbackRate from, in the style of the drift
+	// strategy: TakeProfit = entry ± takeProfitFactor * ATR, StopLoss =
+	// entry ∓ stopLossFactor * ATR. Overridable via PREDICTION_STOP_LOSS_FACTOR,
+	// PREDICTION_TAKE_PROFIT_FACTOR, PREDICTION_PROFIT_FACTOR_WINDOW,
+	// PREDICTION_TRAILING_CALLBACK_RATE, and PREDICTION_TRAILING_ACTIVATION_RATIO
+	// (there's no YAML config loader in this tree to source these from, so
+	// like the other PREDICTION_* knobs above they're environment-only).
+	stopLossFactor          float64
+	baseTakeProfitFactor    float64
+	profitFactorWindow      int
+	trailingCallbackRate    float64
+	trailingActivationRatio float64
+
+	historyMu sync.Mutex
+	history   map[string][]*StockInfo
+
+	profitFactorMu        sync.Mutex
+	realizedProfitFactors map[string][]float64
 }
 
+// maxProfitFactorHistory bounds how many realized profit factors
+// RecordRealizedProfitFactor keeps per ticker, regardless of
+// profitFactorWindow.
+const maxProfitFactorHistory = 120
+
+// maxTrackedSnapshots bounds how many recent snapshots are kept per ticker,
+// regardless of window size, so the ring buffer can't grow unbounded for a
+// ticker that's queried indefinitely.
+const maxTrackedSnapshots = 120
+
 // StockPrediction represents a prediction made by the TensorFlow model
 type StockPrediction struct {
 	PredictedPrice float64
 	Confidence     float64
 	Direction      string // UP, DOWN, NEUTRAL
 	Factors        []string
+
+	// StopLoss/TakeProfit are ATR-derived bracket-order levels for a
+	// position entered at the stock's current price in Direction, and
+	// TrailingCallbackRate is how far (as a fraction of the favorable
+	// extreme) a trailing stop should trail once opened. Zero when there
+	// wasn't enough per-ticker history to compute an ATR; see
+	// TFModelService.attachRiskLevels.
+	StopLoss             float64
+	TakeProfit           float64
+	TrailingCallbackRate float64
 }
 
 // NewTFModelService creates a new TensorFlow model service
@@ -39,8 +100,135 @@ func NewTFModelService() *TFModelService {
 	
 	// We're ready to make predictions, either with the Python bridge or simulated
 	return &TFModelService{
-		modelReady: true,
+		modelReady:              true,
+		atrWindow:               envInt("PREDICTION_ATR_WINDOW", 14),
+		fisherTransformWindow:   envInt("PREDICTION_FISHER_WINDOW", 10),
+		smootherWindow:          envInt("PREDICTION_DRIFT_WINDOW", 20),
+		estimatorName:           envString("PREDICTION_ESTIMATOR", "ols"),
+		stopLossFactor:          envFloat("PREDICTION_STOP_LOSS_FACTOR", 1.5),
+		baseTakeProfitFactor:    envFloat("PREDICTION_TAKE_PROFIT_FACTOR", 2.5),
+		profitFactorWindow:      envInt("PREDICTION_PROFIT_FACTOR_WINDOW", 10),
+		trailingCallbackRate:    envFloat("PREDICTION_TRAILING_CALLBACK_RATE", 0.02),
+		trailingActivationRatio: envFloat("PREDICTION_TRAILING_ACTIVATION_RATIO", 0.01),
+		history:                 make(map[string][]*StockInfo),
+		realizedProfitFactors:   make(map[string][]float64),
+	}
+}
+
+// envInt reads an integer from the environment, falling back to def if the
+// variable is unset or unparsable.
+func envInt(key string, def int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+	parsed, err := strconv.Atoi(val)
+	if err != nil {
+		return def
+	}
+	return parsed
+}
+
+// envString reads a string from the environment, falling back to def if the
+// variable is unset.
+func envString(key, def string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return def
+}
+
+// envFloat reads a float64 from the environment, falling back to def if the
+// variable is unset or unparsable.
+func envFloat(key string, def float64) float64 {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+	parsed, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return def
+	}
+	return parsed
+}
+
+// recordAndGetHistory appends stock's snapshot to its ticker's ring buffer
+// and returns the chronological (oldest-first) closes/highs/lows series
+// accumulated so far, capped at maxTrackedSnapshots.
+func (tf *TFModelService) recordAndGetHistory(stock *StockInfo) (closes, highs, lows []float64) {
+	tf.historyMu.Lock()
+	defer tf.historyMu.Unlock()
+
+	snapshots := append(tf.history[stock.Ticker], stock)
+	if len(snapshots) > maxTrackedSnapshots {
+		snapshots = snapshots[len(snapshots)-maxTrackedSnapshots:]
 	}
+	tf.history[stock.Ticker] = snapshots
+
+	closes = make([]float64, len(snapshots))
+	highs = make([]float64, len(snapshots))
+	lows = make([]float64, len(snapshots))
+	for i, s := range snapshots {
+		closes[i] = s.Price
+		highs[i] = s.High
+		lows[i] = s.Low
+	}
+	return closes, highs, lows
+}
+
+// historySnapshot reads back ticker's currently recorded closes/highs/lows
+// without appending a new snapshot, for callers (like attachRiskLevels) that
+// need the same series a prior recordAndGetHistory call already populated
+// this request.
+func (tf *TFModelService) historySnapshot(ticker string) (closes, highs, lows []float64) {
+	tf.historyMu.Lock()
+	defer tf.historyMu.Unlock()
+
+	snapshots := tf.history[ticker]
+	closes = make([]float64, len(snapshots))
+	highs = make([]float64, len(snapshots))
+	lows = make([]float64, len(snapshots))
+	for i, s := range snapshots {
+		closes[i] = s.Price
+		highs[i] = s.High
+		lows[i] = s.Low
+	}
+	return closes, highs, lows
+}
+
+// seedHistoryFromStore warm-starts ticker's in-memory ring buffer from the
+// shared HistoryStore the first time a prediction is requested for it, so
+// predictions aren't cold-started from just the current live snapshot the
+// way they were before the history-ingestion layer existed.
+func (tf *TFModelService) seedHistoryFromStore(ticker string) {
+	tf.historyMu.Lock()
+	seeded := len(tf.history[ticker]) > 0
+	tf.historyMu.Unlock()
+	if seeded {
+		return
+	}
+
+	bars, err := historyStore.GetHistory(ticker, "1Day", maxTrackedSnapshots)
+	if err != nil || len(bars) == 0 {
+		return
+	}
+
+	snapshots := make([]*StockInfo, len(bars))
+	for i, bar := range bars {
+		snapshots[i] = &StockInfo{
+			Ticker: ticker,
+			Price:  bar.Close,
+			Open:   bar.Open,
+			High:   bar.High,
+			Low:    bar.Low,
+		}
+	}
+
+	tf.historyMu.Lock()
+	if len(tf.history[ticker]) == 0 {
+		tf.history[ticker] = snapshots
+	}
+	tf.historyMu.Unlock()
 }
 
 // PredictStockMovement predicts future stock movements based on current data
@@ -48,111 +236,367 @@ func (tf *TFModelService) PredictStockMovement(stock *StockInfo) (*StockPredicti
 	if !tf.modelReady {
 		return nil, fmt.Errorf("tensorflow model not initialized")
 	}
-	
+
+	tf.seedHistoryFromStore(stock.Ticker)
+
 	// Try to use the Python bridge for predictions
 	bridge := GetPythonBridge()
 	if bridge.initialized {
 		// Make prediction using Python - try our simple analyzer first
-		result, err := bridge.PredictStockPriceWithSimpleAnalyzer(stock.Ticker)
+		result, err := bridge.PredictStockPriceWithSimpleAnalyzer(context.Background(), stock.Ticker)
 		if err == nil && result != nil {
 			// Use the Python prediction results
 			log.Printf("Using Python-based prediction for %s", stock.Ticker)
-			return &StockPrediction{
+			prediction := &StockPrediction{
 				PredictedPrice: result.PredictedPrice,
 				Confidence:     result.Confidence,
 				Direction:      result.Direction,
 				Factors:        result.Factors,
-			}, nil
+			}
+			tf.attachRiskLevels(stock, prediction)
+			return prediction, nil
 		}
-		
+
 		// Try the original prediction method as a backup
-		result, err = bridge.PredictStockPrice(stock.Ticker)
+		result, err = bridge.PredictStockPrice(context.Background(), stock.Ticker)
 		if err == nil && result != nil {
 			log.Printf("Using TensorFlow-based prediction for %s", stock.Ticker)
-			return &StockPrediction{
+			prediction := &StockPrediction{
 				PredictedPrice: result.PredictedPrice,
 				Confidence:     result.Confidence,
 				Direction:      result.Direction,
 				Factors:        result.Factors,
-			}, nil
+			}
+			tf.attachRiskLevels(stock, prediction)
+			return prediction, nil
 		}
-		
+
 		// Log the error but continue with fallback
 		log.Printf("Python prediction failed: %v, using fallback", err)
 	}
 
-	// Fallback to simple prediction
-	log.Printf("Using simple prediction for %s", stock.Ticker)
-	
-	// Simple prediction based on price movement
-	changePercent := 0.0
-	if stock.Change != 0 && stock.Price != 0 {
-		changePercent = (stock.Change / stock.Price) * 100
+	// If we have real technical indicators, drive the prediction off of
+	// them instead of the OHLC-snapshot heuristics below.
+	if stock.Technical != nil {
+		prediction, err := tf.predictFromIndicators(stock)
+		if err == nil && prediction != nil {
+			tf.attachRiskLevels(stock, prediction)
+		}
+		return prediction, err
 	}
-	
-	// Simulate some predictive model output
-	randomFactor := (math.Sin(float64(time.Now().Unix())) + 1.0) * 0.5 // 0.0-1.0
-	if randomFactor > 0.5 {
-		changePercent *= 1.2 // Amplify the trend
+
+	// Fallback to the pluggable PriceEstimator (OLS/kNN/GBDT/Python),
+	// dropping back to the deterministic indicator simulation when there
+	// isn't enough history yet to fit one.
+	prediction, err := tf.predictWithEstimator(stock, "")
+	if err == nil && prediction != nil {
+		tf.attachRiskLevels(stock, prediction)
+	}
+	return prediction, err
+}
+
+// PredictStockMovementWithEstimator behaves like PredictStockMovement but
+// lets a caller (e.g. a `?estimator=` query param) pick which PriceEstimator
+// drives the fallback path used when stock.Technical is nil.
+func (tf *TFModelService) PredictStockMovementWithEstimator(stock *StockInfo, estimatorName string) (*StockPrediction, error) {
+	if !tf.modelReady {
+		return nil, fmt.Errorf("tensorflow model not initialized")
+	}
+
+	tf.seedHistoryFromStore(stock.Ticker)
+
+	var prediction *StockPrediction
+	var err error
+	if stock.Technical != nil {
+		prediction, err = tf.predictFromIndicators(stock)
 	} else {
-		changePercent *= -0.8 // Reverse the trend somewhat
+		prediction, err = tf.predictWithEstimator(stock, estimatorName)
 	}
-	
-	// Predict price (limited to +/- 5%)
-	changePercent = math.Max(-5.0, math.Min(5.0, changePercent))
-	predictedChange := stock.Price * (changePercent / 100.0)
-	predictedPrice := stock.Price + predictedChange
-	
-	// Round to 2 decimal places
-	predictedPrice = math.Round(predictedPrice*100) / 100
-	
-	// Determine direction
+	if err == nil && prediction != nil {
+		tf.attachRiskLevels(stock, prediction)
+	}
+	return prediction, err
+}
+
+// minSamplesForEstimator is the fewest lag-feature/target training pairs
+// predictWithEstimator needs before trusting a freshly fit PriceEstimator
+// over the plain indicator-driven simulation.
+const minSamplesForEstimator = 10
+
+// predictWithEstimator fits estimatorOverride's PriceEstimator (or, if
+// empty, tf.estimatorName's) on this ticker's recent close-price history and
+// uses it to forecast the next close, falling back to simulatePrediction's
+// indicator-driven heuristics when there isn't enough history yet or the
+// estimator can't be resolved, fit, or queried.
+func (tf *TFModelService) predictWithEstimator(stock *StockInfo, estimatorOverride string) (*StockPrediction, error) {
+	closes, _, _ := tf.recordAndGetHistory(stock)
+	features, targets := buildLagFeatures(closes)
+	if len(features) < minSamplesForEstimator {
+		return tf.simulatePrediction(stock, tf.extractFeatures(stock)), nil
+	}
+
+	name := tf.resolveEstimatorName(estimatorOverride)
+	est, err := newPriceEstimator(name)
+	if err != nil {
+		log.Printf("Unknown prediction estimator %q, using indicator simulation: %v", name, err)
+		return tf.simulatePrediction(stock, tf.extractFeatures(stock)), nil
+	}
+	if pythonEst, ok := est.(*PythonEstimator); ok {
+		pythonEst.Ticker = stock.Ticker
+	}
+
+	if err := est.Fit(features, targets); err != nil {
+		log.Printf("Estimator %q fit failed for %s, using indicator simulation: %v", name, stock.Ticker, err)
+		return tf.simulatePrediction(stock, tf.extractFeatures(stock)), nil
+	}
+
+	latest := []float64{closes[len(closes)-1], closes[len(closes)-2]}
+	predictedPrice, stddev, err := est.Predict(latest)
+	if err != nil {
+		log.Printf("Estimator %q predict failed for %s, using indicator simulation: %v", name, stock.Ticker, err)
+		return tf.simulatePrediction(stock, tf.extractFeatures(stock)), nil
+	}
+
+	changePct := 0.0
+	if stock.Price != 0 {
+		changePct = (predictedPrice - stock.Price) / stock.Price * 100
+	}
+
+	// A freshly fit estimator can extrapolate well past a plausible
+	// single-call move (e.g. a thin lag-feature series), so bound it the
+	// same way simulatePrediction bounds its own combined signal.
+	changePct = math.Max(-5.0, math.Min(5.0, changePct))
+	predictedPrice = math.Round(stock.Price*(1+changePct/100)*100) / 100
+
 	direction := "NEUTRAL"
-	if predictedChange > 0 {
+	if changePct > 1.0 {
 		direction = "UP"
-	} else if predictedChange < 0 {
+	} else if changePct < -1.0 {
 		direction = "DOWN"
 	}
-	
-	// Generate confidence (60-90%)
-	confidence := 60.0 + (randomFactor * 30.0)
-	
-	// Generate prediction factors
-	factors := []string{}
-	
-	// Price momentum
-	if stock.Change > 0 {
-		factors = append(factors, "Recent positive price momentum")
-	} else if stock.Change < 0 {
-		factors = append(factors, "Recent negative price momentum")
+
+	return &StockPrediction{
+		PredictedPrice: predictedPrice,
+		Confidence:     confidenceFromStdDev(stddev, stock.Price),
+		Direction:      direction,
+		Factors:        tf.identifyKeyFactors(stock, tf.extractFeatures(stock), changePct),
+	}, nil
+}
+
+// resolveEstimatorName picks override if non-empty (a per-request
+// selection), otherwise the estimator configured via PREDICTION_ESTIMATOR.
+func (tf *TFModelService) resolveEstimatorName(override string) string {
+	if override != "" {
+		return override
 	}
-	
-	// Price position relative to day's range
-	dayRange := stock.High - stock.Low
-	if dayRange > 0 {
-		pricePosition := (stock.Price - stock.Low) / dayRange
-		if pricePosition > 0.8 {
-			factors = append(factors, "Price near daily high")
-		} else if pricePosition < 0.2 {
-			factors = append(factors, "Price near daily low")
-		}
+	return tf.estimatorName
+}
+
+// newPriceEstimator resolves name to a PriceEstimator, treating "python" as
+// the existing Python bridge rather than one of internal/estimator's
+// in-process models.
+func newPriceEstimator(name string) (estimator.PriceEstimator, error) {
+	if name == "python" {
+		return &PythonEstimator{}, nil
 	}
-	
-	// Market conditions
-	currentHour := time.Now().Hour()
-	if currentHour < 12 {
-		factors = append(factors, "Morning market conditions")
-	} else if currentHour >= 12 && currentHour < 16 {
-		factors = append(factors, "Afternoon trading patterns")
+	return estimator.New(name)
+}
+
+// buildLagFeatures turns a chronological closes series into lag-2
+// regression pairs: each row is [closes[i-1], closes[i-2]], predicting
+// closes[i]. This trains the estimator off the same per-ticker snapshot
+// history simulatePrediction already accumulates, without needing a
+// separate feature store.
+func buildLagFeatures(closes []float64) (features [][]float64, targets []float64) {
+	for i := 2; i < len(closes); i++ {
+		features = append(features, []float64{closes[i-1], closes[i-2]})
+		targets = append(targets, closes[i])
+	}
+	return features, targets
+}
+
+// confidenceFromStdDev turns a PriceEstimator's residual/neighbor stddev
+// into a confidence score: a tighter spread relative to price means a
+// higher confidence, clamped to the same [0.35, 0.95] band
+// calculateConfidence uses.
+func confidenceFromStdDev(stddev, price float64) float64 {
+	if price == 0 {
+		return 0.5
+	}
+	relativeSpread := stddev / price
+	confidence := 0.95 - relativeSpread*5
+	return math.Max(0.35, math.Min(0.95, confidence))
+}
+
+// attachRiskLevels fills in prediction's StopLoss/TakeProfit/
+// TrailingCallbackRate from RiskLevels, leaving them at zero if there isn't
+// enough per-ticker history yet to compute an ATR.
+func (tf *TFModelService) attachRiskLevels(stock *StockInfo, prediction *StockPrediction) {
+	stopLoss, takeProfit, trailingCallbackRate, err := tf.RiskLevels(stock, prediction.Direction)
+	if err != nil {
+		return
+	}
+	prediction.StopLoss = stopLoss
+	prediction.TakeProfit = takeProfit
+	prediction.TrailingCallbackRate = trailingCallbackRate
+}
+
+// RiskLevels computes bracket-order levels for a position in stock entered
+// at its current price, in direction ("UP"/"NEUTRAL" for long, "DOWN" for
+// short), in the style of the drift strategy: TakeProfit = entry ±
+// takeProfitFactor * ATR(atrWindow), StopLoss = entry ∓ stopLossFactor *
+// ATR, with takeProfitFactor smoothed by smoothedTakeProfitFactor so it
+// adapts to the ticker's recent realized performance. Returns an error if
+// there isn't enough per-ticker OHLC history yet to compute a positive ATR.
+func (tf *TFModelService) RiskLevels(stock *StockInfo, direction string) (stopLoss, takeProfit, trailingCallbackRate float64, err error) {
+	closes, highs, lows := tf.historySnapshot(stock.Ticker)
+	atr := indicators.ATR(highs, lows, closes, tf.atrWindow)
+	if atr <= 0 {
+		return 0, 0, 0, fmt.Errorf("tensorflow: not enough history for %s to compute ATR", stock.Ticker)
+	}
+
+	entry := stock.Price
+	takeProfitFactor := tf.smoothedTakeProfitFactor(stock.Ticker)
+
+	if direction == "DOWN" {
+		stopLoss = entry + tf.stopLossFactor*atr
+		takeProfit = entry - takeProfitFactor*atr
 	} else {
-		factors = append(factors, "After-hours sentiment")
+		stopLoss = entry - tf.stopLossFactor*atr
+		takeProfit = entry + takeProfitFactor*atr
 	}
-	
-	// If we don't have enough factors, add a generic one
-	if len(factors) < 2 {
-		factors = append(factors, "Based on technical analysis")
+	return stopLoss, takeProfit, tf.trailingCallbackRate, nil
+}
+
+// RecordRealizedProfitFactor feeds a closed position's realized
+// take-profit-to-stop-loss ratio into ticker's rolling window, so future
+// RiskLevels calls smooth takeProfitFactor toward what's actually been
+// working for that ticker instead of the static baseTakeProfitFactor.
+func (tf *TFModelService) RecordRealizedProfitFactor(ticker string, profitFactor float64) {
+	tf.profitFactorMu.Lock()
+	defer tf.profitFactorMu.Unlock()
+
+	history := append(tf.realizedProfitFactors[ticker], profitFactor)
+	if len(history) > maxProfitFactorHistory {
+		history = history[len(history)-maxProfitFactorHistory:]
 	}
-	
+	tf.realizedProfitFactors[ticker] = history
+}
+
+// smoothedTakeProfitFactor returns baseTakeProfitFactor smoothed by the SMA
+// of ticker's last profitFactorWindow realized profit factors, or the base
+// value unchanged if none have been recorded yet.
+func (tf *TFModelService) smoothedTakeProfitFactor(ticker string) float64 {
+	tf.profitFactorMu.Lock()
+	history := tf.realizedProfitFactors[ticker]
+	tf.profitFactorMu.Unlock()
+
+	if len(history) == 0 {
+		return tf.baseTakeProfitFactor
+	}
+
+	window := tf.profitFactorWindow
+	if window > len(history) {
+		window = len(history)
+	}
+	recent := history[len(history)-window:]
+
+	var sum float64
+	for _, pf := range recent {
+		sum += pf
+	}
+	return sum / float64(window)
+}
+
+// TrailingStop ratchets stopLoss for an open position once price has moved
+// at least trailingActivationRatio away from entry in the favorable
+// direction: for a long ("UP"/"NEUTRAL") it trails trailingCallbackRate
+// below extreme, the highest price seen since entry; for a short ("DOWN")
+// it trails above extreme, the lowest price seen. Safe to call on every
+// price tick - it only ever moves the stop in the favorable direction.
+func (tf *TFModelService) TrailingStop(direction string, entry, extreme, stopLoss float64) float64 {
+	if entry == 0 {
+		return stopLoss
+	}
+
+	if direction == "DOWN" {
+		if (entry-extreme)/entry < tf.trailingActivationRatio {
+			return stopLoss
+		}
+		if trailing := extreme * (1 + tf.trailingCallbackRate); trailing < stopLoss {
+			return trailing
+		}
+		return stopLoss
+	}
+
+	if (extreme-entry)/entry < tf.trailingActivationRatio {
+		return stopLoss
+	}
+	if trailing := extreme * (1 - tf.trailingCallbackRate); trailing > stopLoss {
+		return trailing
+	}
+	return stopLoss
+}
+
+// predictFromIndicators derives a prediction from stock.Technical's
+// indicator crossings rather than the OHLC-snapshot heuristics, so
+// KeyFactors describe concrete signals ("MACD bullish crossover", "RSI
+// oversold at 28") instead of generic momentum/time-of-day text.
+func (tf *TFModelService) predictFromIndicators(stock *StockInfo) (*StockPrediction, error) {
+	t := stock.Technical
+
+	// Score each indicator's directional lean in the same units as a
+	// predicted percentage price change, then sum them into one estimate.
+	var score float64
+	if t.MACDHistogram > 0 {
+		score += 1.0
+	} else if t.MACDHistogram < 0 {
+		score -= 1.0
+	}
+
+	switch {
+	case t.RSI14 <= 30:
+		score += 1.5 // oversold, likely to bounce
+	case t.RSI14 >= 70:
+		score -= 1.5 // overbought, likely to pull back
+	}
+
+	if t.SMA20 > t.SMA50 {
+		score += 0.5
+	} else if t.SMA20 < t.SMA50 {
+		score -= 0.5
+	}
+
+	changePercent := math.Max(-5.0, math.Min(5.0, score))
+	predictedPrice := math.Round(stock.Price*(1+changePercent/100)*100) / 100
+
+	direction := "NEUTRAL"
+	if changePercent > 0.5 {
+		direction = "UP"
+	} else if changePercent < -0.5 {
+		direction = "DOWN"
+	}
+
+	// Confidence reflects how many of the three signals agree with the
+	// overall direction.
+	agreement := 0
+	total := 0
+	for _, signal := range []float64{t.MACDHistogram, 70 - t.RSI14, t.SMA20 - t.SMA50} {
+		total++
+		if (score > 0 && signal > 0) || (score < 0 && signal < 0) {
+			agreement++
+		}
+	}
+	confidence := 0.5
+	if total > 0 {
+		confidence = 0.4 + 0.5*(float64(agreement)/float64(total))
+	}
+
+	factors := t.Signals
+	if len(factors) == 0 {
+		factors = []string{"Technical indicators show no strong signal"}
+	}
+
 	return &StockPrediction{
 		PredictedPrice: predictedPrice,
 		Confidence:     confidence,
@@ -182,24 +626,8 @@ func (tf *TFModelService) extractFeatures(stock *StockInfo) map[string]float64 {
 	volatility := dayRange / stock.Price * 100
 	
 	// Market cap numeric value
-	marketCapValue := 0.0
-	if strings.HasPrefix(stock.MarketCap, "$") {
-		mcStr := strings.TrimPrefix(stock.MarketCap, "$")
-		if strings.HasSuffix(mcStr, "T") {
-			if val, err := strconv.ParseFloat(strings.TrimSuffix(mcStr, "T"), 64); err == nil {
-				marketCapValue = val * 1e12
-			}
-		} else if strings.HasSuffix(mcStr, "B") {
-			if val, err := strconv.ParseFloat(strings.TrimSuffix(mcStr, "B"), 64); err == nil {
-				marketCapValue = val * 1e9
-			}
-		} else if strings.HasSuffix(mcStr, "M") {
-			if val, err := strconv.ParseFloat(strings.TrimSuffix(mcStr, "M"), 64); err == nil {
-				marketCapValue = val * 1e6
-			}
-		}
-	}
-	
+	marketCapValue := parseMarketCapValue(stock.MarketCap)
+
 	return map[string]float64{
 		"price_position": pricePosition,
 		"change_pct":     changePct,
@@ -213,14 +641,29 @@ func (tf *TFModelService) extractFeatures(stock *StockInfo) map[string]float64 {
 
 // simulatePrediction simulates a TensorFlow prediction
 func (tf *TFModelService) simulatePrediction(stock *StockInfo, features map[string]float64) *StockPrediction {
-	// In a real implementation, this would use the TensorFlow model to make predictions
-	// For now, we'll use a rule-based system with some randomness to simulate predictions
-	
-	// Calculate base prediction
+	// Build up (or extend) this ticker's recent-snapshot ring buffer so the
+	// indicators below have a real series to work from instead of only the
+	// current OHLC snapshot.
+	closes, highs, lows := tf.recordAndGetHistory(stock)
+
+	// Momentum: prefer the Drift indicator (SMA of log-returns over
+	// stddev) once enough history has accumulated; it's noise-discounted
+	// and deterministic, unlike the single-snapshot change_pct feature.
 	momentumFactor := features["change_pct"] * 0.1
+	if driftSignal := indicators.Drift(closes, tf.smootherWindow); driftSignal != 0 {
+		momentumFactor = driftSignal * 0.5
+	}
+
 	positionFactor := (features["price_position"] - 0.5) * -0.2 // Mean reversion
+
+	// Volatility: prefer ATR (expressed as a % of price) once there's
+	// enough history; it accounts for gaps the same-day high/low spread
+	// used in the feature map misses.
 	volatilityFactor := features["volatility"] * 0.05
-	
+	if atr := indicators.ATR(highs, lows, closes, tf.atrWindow); atr > 0 {
+		volatilityFactor = (atr / stock.Price * 100) * 0.05
+	}
+
 	// Market cap factor - larger companies tend to be more stable
 	marketCapFactor := 0.0
 	if features["market_cap"] > 1e11 { // $100B+
@@ -228,10 +671,10 @@ func (tf *TFModelService) simulatePrediction(stock *StockInfo, features map[stri
 	} else if features["market_cap"] < 1e9 { // Less than $1B
 		marketCapFactor = 0.2 // More volatile
 	}
-	
+
 	// Calculate predicted change percentage
 	predictedChangePct := momentumFactor + positionFactor + volatilityFactor + marketCapFactor
-	
+
 	// Current day simulation
 	intraday := time.Now().Hour() < 16 // Before market close
 	if intraday {
@@ -242,17 +685,25 @@ func (tf *TFModelService) simulatePrediction(stock *StockInfo, features map[stri
 			predictedChangePct += 0.3 // More likely to bounce if already near low
 		}
 	}
-	
-	// Add some controlled randomness
-	randomFactor := (math.Sin(float64(time.Now().UnixNano())) * 0.5)
-	predictedChangePct += randomFactor
-	
+
+	// Fisher Transform confirms or dampens the signal above: it's bounded
+	// but sharpens near the edges of the recent trading range, the same
+	// role the old math.Sin(time.Now().UnixNano()) jitter played, but
+	// deterministic and reproducible for backtesting.
+	fisherSignal := indicators.FisherTransform(closes, tf.fisherTransformWindow)
+	predictedChangePct += fisherSignal * 0.5
+
+	// Bound the combined signal the same way the real-model fallback path
+	// above does, so a pile-up of factors can't simulate an implausible
+	// single-call price swing.
+	predictedChangePct = math.Max(-5.0, math.Min(5.0, predictedChangePct))
+
 	// Calculate predicted price with more precision
 	predictedPrice := stock.Price * (1 + predictedChangePct/100)
-	
+
 	// Round to 2 decimal places for better display
 	predictedPrice = math.Round(predictedPrice*100) / 100
-	
+
 	// Determine direction with clearer thresholds
 	direction := "NEUTRAL"
 	if predictedChangePct > 1.0 {
@@ -260,14 +711,14 @@ func (tf *TFModelService) simulatePrediction(stock *StockInfo, features map[stri
 	} else if predictedChangePct < -1.0 {
 		direction = "DOWN"
 	}
-	
+
 	// Calculate confidence based on consistency of signals
-	signals := []float64{momentumFactor, positionFactor, volatilityFactor, marketCapFactor, randomFactor}
+	signals := []float64{momentumFactor, positionFactor, volatilityFactor, marketCapFactor, fisherSignal}
 	confidence := tf.calculateConfidence(signals, predictedChangePct)
-	
+
 	// Identify key factors driving the prediction
 	factors := tf.identifyKeyFactors(stock, features, predictedChangePct)
-	
+
 	return &StockPrediction{
 		PredictedPrice: predictedPrice,
 		Confidence:     confidence,
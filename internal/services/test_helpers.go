@@ -1,8 +1,6 @@
 package services
 
-import (
-	"time"
-)
+import "strings"
 
 // Exported functions to support testing
 
@@ -16,20 +14,83 @@ func FormatMarketCap(marketCap int64) string {
 	return formatMarketCap(marketCap)
 }
 
+// RuleBasedRecommendation is GetRuleBasedRecommendation's result: the text
+// verdict plus the ATR-derived bracket-order levels (see
+// TFModelService.RiskLevels) a user would need to place a matching
+// stop-loss/take-profit order.
+type RuleBasedRecommendation struct {
+	Text                 string
+	StopLoss             float64
+	TakeProfit           float64
+	TrailingCallbackRate float64
+}
+
 // GetRuleBasedRecommendation provides a rule-based stock recommendation
-func GetRuleBasedRecommendation(stock *StockInfo) string {
+func GetRuleBasedRecommendation(stock *StockInfo) *RuleBasedRecommendation {
+	rec := &RuleBasedRecommendation{Text: ruleBasedRecommendationText(stock)}
+
+	if tfModelService != nil {
+		direction := "UP"
+		if strings.Contains(rec.Text, "SELL") {
+			direction = "DOWN"
+		}
+		if stopLoss, takeProfit, trailingCallbackRate, err := tfModelService.RiskLevels(stock, direction); err == nil {
+			rec.StopLoss = stopLoss
+			rec.TakeProfit = takeProfit
+			rec.TrailingCallbackRate = trailingCallbackRate
+		}
+	}
+
+	return rec
+}
+
+// ruleBasedRecommendationText is GetRuleBasedRecommendation's text-only
+// verdict, kept separate so risk levels can be attached without duplicating
+// this selection logic.
+func ruleBasedRecommendationText(stock *StockInfo) string {
+	// Prefer the multi-factor regression's continuous score once enough
+	// history has accumulated to fit it.
+	if factorModelService != nil {
+		if score, err := factorModelService.Score(stock); err == nil {
+			return score.Recommendation()
+		}
+	}
+
 	if aiService != nil {
 		return aiService.getRuleBasedRecommendation(stock)
 	}
-	
-	// Fallback implementation
-	if stock.Change > 0 {
+
+	// Fallback implementation: use the ticker's backtested best-performing
+	// change-percent threshold if one has been computed, rather than a flat
+	// stock.Change > 0 rule.
+	threshold, ok := BacktestThreshold(stock.Ticker)
+	if !ok {
+		threshold = 0
+	}
+	if calculateChangePercentage(stock.Change, stock.Price) > threshold {
 		return "BUY - Stock shows positive momentum"
-	} else {
-		return "HOLD - Stock shows negative momentum"
+	}
+	return "HOLD - Stock shows negative momentum"
+}
+
+// RecordRealizedProfitFactor feeds a closed position's realized
+// take-profit-to-stop-loss ratio back into the TF model's risk-level
+// smoothing for ticker.
+func RecordRealizedProfitFactor(ticker string, profitFactor float64) {
+	if tfModelService != nil {
+		tfModelService.RecordRealizedProfitFactor(ticker, profitFactor)
 	}
 }
 
+// TrailingStop ratchets stopLoss for an open position once price has moved
+// far enough in the favorable direction; see TFModelService.TrailingStop.
+func TrailingStop(direction string, entry, extreme, stopLoss float64) float64 {
+	if tfModelService != nil {
+		return tfModelService.TrailingStop(direction, entry, extreme, stopLoss)
+	}
+	return stopLoss
+}
+
 // PredictStockMovement predicts stock price movement using TF model
 func PredictStockMovement(stock *StockInfo) (*StockPrediction, error) {
 	if tfModelService != nil {
@@ -38,24 +99,24 @@ func PredictStockMovement(stock *StockInfo) (*StockPrediction, error) {
 	return nil, nil
 }
 
+// PredictStockMovementWithEstimator predicts stock price movement using a
+// caller-selected PriceEstimator for the TF model's fallback path.
+func PredictStockMovementWithEstimator(stock *StockInfo, estimatorName string) (*StockPrediction, error) {
+	if tfModelService != nil {
+		return tfModelService.PredictStockMovementWithEstimator(stock, estimatorName)
+	}
+	return nil, nil
+}
+
 // CacheStockInfo adds a stock to the cache
 func CacheStockInfo(key string, stock *StockInfo) {
-	stockCache[key] = &CachedStock{
-		Data:      stock,
-		Timestamp: now(),
-	}
+	cacheSet(key, stock)
 }
 
 // GetCachedStock retrieves a stock from cache
 func GetCachedStock(key string) *StockInfo {
-	if cached, ok := stockCache[key]; ok {
-		cached.Data.DataAge = int64(now().Sub(cached.Timestamp).Seconds())
-		return cached.Data
+	if cached, ok := cacheGet(key); ok {
+		return cached
 	}
 	return nil
 }
-
-// Wrapper for time.Now() to make testing easier
-func now() time.Time {
-	return time.Now()
-}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// yahooAuthTTL is how long a fetched crumb/cookie pair is trusted before
+// yahooAuth forces a refresh, independent of any 401/403-triggered rotation.
+const yahooAuthTTL = time.Hour
+
+// yahooAuth holds the crumb and cookies (A3/B from fc.yahoo.com) Yahoo's
+// quote endpoint now requires, refreshing them on first use and whenever
+// invalidate is called after a 401/403.
+type yahooAuth struct {
+	mu        sync.RWMutex
+	cookies   []*http.Cookie
+	crumb     string
+	expiresAt time.Time
+	client    *http.Client
+}
+
+// globalYahooAuth is shared by every yahooQuoteProvider call so the crumb
+// handshake only happens once per hour (or per rotation) regardless of how
+// many goroutines are fetching quotes concurrently.
+var globalYahooAuth = &yahooAuth{client: &http.Client{Timeout: 10 * time.Second}}
+
+const yahooUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"
+
+// yahooCookieURL and yahooCrumbURL are vars rather than consts so tests can
+// point the handshake at an httptest.Server instead of the real Yahoo
+// endpoints.
+var (
+	yahooCookieURL = "https://fc.yahoo.com"
+	yahooCrumbURL  = "https://query2.finance.yahoo.com/v1/test/getcrumb"
+)
+
+// credentials returns a cached crumb/cookie pair if still fresh, refreshing
+// it (under a. write lock) otherwise.
+func (a *yahooAuth) credentials() (string, []*http.Cookie, error) {
+	a.mu.RLock()
+	if a.crumb != "" && time.Now().Before(a.expiresAt) {
+		crumb, cookies := a.crumb, a.cookies
+		a.mu.RUnlock()
+		return crumb, cookies, nil
+	}
+	a.mu.RUnlock()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Another goroutine may have refreshed while we waited for the lock.
+	if a.crumb != "" && time.Now().Before(a.expiresAt) {
+		return a.crumb, a.cookies, nil
+	}
+
+	cookies, err := a.fetchCookies()
+	if err != nil {
+		return "", nil, err
+	}
+
+	crumb, err := a.fetchCrumb(cookies)
+	if err != nil {
+		return "", nil, err
+	}
+
+	a.cookies = cookies
+	a.crumb = crumb
+	a.expiresAt = time.Now().Add(yahooAuthTTL)
+	return crumb, cookies, nil
+}
+
+// invalidate clears the cached crumb/cookies so the next credentials() call
+// re-runs the handshake, used after a quote request comes back 401/403.
+func (a *yahooAuth) invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.crumb = ""
+	a.cookies = nil
+}
+
+// fetchCookies performs the GET against fc.yahoo.com that seeds the A3/B
+// session cookies a crumb request must carry.
+func (a *yahooAuth) fetchCookies() ([]*http.Cookie, error) {
+	req, err := http.NewRequest("GET", yahooCookieURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", yahooUserAgent)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("yahoo auth: fc.yahoo.com request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	cookies := resp.Cookies()
+	if len(cookies) == 0 {
+		return nil, fmt.Errorf("yahoo auth: fc.yahoo.com returned no cookies")
+	}
+	return cookies, nil
+}
+
+// fetchCrumb performs the GET against query2.finance.yahoo.com's
+// getcrumb endpoint, carrying cookies from fetchCookies.
+func (a *yahooAuth) fetchCrumb(cookies []*http.Cookie) (string, error) {
+	req, err := http.NewRequest("GET", yahooCrumbURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", yahooUserAgent)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("yahoo auth: getcrumb request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("yahoo auth: failed to read getcrumb response: %v", err)
+	}
+
+	crumb := strings.TrimSpace(string(body))
+	if crumb == "" || resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("yahoo auth: getcrumb returned status %d with empty or invalid crumb", resp.StatusCode)
+	}
+	return crumb, nil
+}
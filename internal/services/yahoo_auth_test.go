@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestYahooAuthHandshakeAndRotationOn401 simulates the full crumb handshake
+// (fc.yahoo.com cookie, then getcrumb) plus a forced re-auth when the quote
+// endpoint first responds 401, verifying the crumb/cookie pair is rotated
+// and the request retried exactly once.
+func TestYahooAuthHandshakeAndRotationOn401(t *testing.T) {
+	var crumbCalls, quoteCalls int32
+
+	cookieServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "B", Value: "test-b-cookie"})
+		http.SetCookie(w, &http.Cookie{Name: "A3", Value: "test-a3-cookie"})
+	}))
+	defer cookieServer.Close()
+
+	crumbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("B"); err != nil {
+			t.Errorf("getcrumb request missing B cookie from the fc.yahoo.com handshake: %v", err)
+		}
+		n := atomic.AddInt32(&crumbCalls, 1)
+		fmt.Fprintf(w, "test-crumb-%d", n)
+	}))
+	defer crumbServer.Close()
+
+	quoteServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&quoteCalls, 1)
+		if n == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("crumb") == "" {
+			t.Error("quote request missing crumb query parameter")
+		}
+		fmt.Fprint(w, `{"quoteResponse":{"result":[{"symbol":"AAPL","regularMarketPrice":150.25}]}}`)
+	}))
+	defer quoteServer.Close()
+
+	origCookieURL, origCrumbURL, origQuoteURL := yahooCookieURL, yahooCrumbURL, yahooQuoteBaseURL
+	origAuth := globalYahooAuth
+	yahooCookieURL, yahooCrumbURL, yahooQuoteBaseURL = cookieServer.URL, crumbServer.URL, quoteServer.URL
+	globalYahooAuth = &yahooAuth{client: &http.Client{Timeout: 5 * time.Second}}
+	defer func() {
+		yahooCookieURL, yahooCrumbURL, yahooQuoteBaseURL = origCookieURL, origCrumbURL, origQuoteURL
+		globalYahooAuth = origAuth
+	}()
+
+	resp, err := fetchYahooQuotes("AAPL")
+	if err != nil {
+		t.Fatalf("fetchYahooQuotes failed: %v", err)
+	}
+	if len(resp.QuoteResponse.Result) != 1 || resp.QuoteResponse.Result[0].Symbol != "AAPL" {
+		t.Fatalf("expected one AAPL result, got %+v", resp.QuoteResponse.Result)
+	}
+	if quoteCalls != 2 {
+		t.Errorf("expected the quote endpoint to be retried once after the 401, got %d calls", quoteCalls)
+	}
+	if crumbCalls != 2 {
+		t.Errorf("expected the crumb to be re-fetched once after invalidate(), got %d calls", crumbCalls)
+	}
+
+	// A second, successful fetch should reuse the cached crumb rather than
+	// re-running the handshake.
+	if _, err := fetchYahooQuotes("AAPL"); err != nil {
+		t.Fatalf("second fetchYahooQuotes failed: %v", err)
+	}
+	if crumbCalls != 2 {
+		t.Errorf("expected cached crumb to be reused, but crumb endpoint was called again (now %d calls)", crumbCalls)
+	}
+}
+
+// TestYahooAuthCredentialsCachesUntilInvalidated verifies credentials()
+// only hits the handshake endpoints once across repeated calls, and again
+// after an explicit invalidate().
+func TestYahooAuthCredentialsCachesUntilInvalidated(t *testing.T) {
+	var cookieCalls, crumbCalls int32
+
+	cookieServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&cookieCalls, 1)
+		http.SetCookie(w, &http.Cookie{Name: "B", Value: "test-b-cookie"})
+	}))
+	defer cookieServer.Close()
+
+	crumbServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&crumbCalls, 1)
+		fmt.Fprint(w, "test-crumb")
+	}))
+	defer crumbServer.Close()
+
+	origCookieURL, origCrumbURL := yahooCookieURL, yahooCrumbURL
+	origAuth := globalYahooAuth
+	yahooCookieURL, yahooCrumbURL = cookieServer.URL, crumbServer.URL
+	globalYahooAuth = &yahooAuth{client: &http.Client{Timeout: 5 * time.Second}}
+	defer func() {
+		yahooCookieURL, yahooCrumbURL = origCookieURL, origCrumbURL
+		globalYahooAuth = origAuth
+	}()
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := globalYahooAuth.credentials(); err != nil {
+			t.Fatalf("credentials() call %d failed: %v", i, err)
+		}
+	}
+	if cookieCalls != 1 || crumbCalls != 1 {
+		t.Errorf("expected exactly one handshake across repeated calls, got %d cookie calls and %d crumb calls", cookieCalls, crumbCalls)
+	}
+
+	globalYahooAuth.invalidate()
+	if _, _, err := globalYahooAuth.credentials(); err != nil {
+		t.Fatalf("credentials() after invalidate failed: %v", err)
+	}
+	if cookieCalls != 2 || crumbCalls != 2 {
+		t.Errorf("expected a second handshake after invalidate(), got %d cookie calls and %d crumb calls", cookieCalls, crumbCalls)
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// yahooQuoteProvider fetches quotes from Yahoo Finance's v7 quote endpoint.
+// It fills in fields the other providers leave as "N/A": market cap, 52-week
+// high/low, average volume, P/E ratio, and dividend yield, plus pre-market
+// and after-hours pricing.
+type yahooQuoteProvider struct{}
+
+func (yahooQuoteProvider) Name() string            { return "yahoo" }
+func (yahooQuoteProvider) RateLimit() time.Duration { return 500 * time.Millisecond }
+
+// yahooQuoteResponse is the shape of a v7 quote response; both Fetch and
+// FetchBatch parse into it since the endpoint returns an array either way.
+type yahooQuoteResponse struct {
+	QuoteResponse struct {
+		Result []yahooQuoteResult `json:"result"`
+	} `json:"quoteResponse"`
+}
+
+type yahooQuoteResult struct {
+	Symbol                      string  `json:"symbol"`
+	ShortName                   string  `json:"shortName"`
+	LongName                    string  `json:"longName"`
+	RegularMarketPrice          float64 `json:"regularMarketPrice"`
+	RegularMarketChange         float64 `json:"regularMarketChange"`
+	RegularMarketChangePercent  float64 `json:"regularMarketChangePercent"`
+	RegularMarketOpen           float64 `json:"regularMarketOpen"`
+	RegularMarketDayHigh        float64 `json:"regularMarketDayHigh"`
+	RegularMarketDayLow         float64 `json:"regularMarketDayLow"`
+	RegularMarketVolume         int64   `json:"regularMarketVolume"`
+	MarketCap                   int64   `json:"marketCap"`
+	FiftyTwoWeekHigh            float64 `json:"fiftyTwoWeekHigh"`
+	FiftyTwoWeekLow             float64 `json:"fiftyTwoWeekLow"`
+	AverageDailyVolume3Month    int64   `json:"averageDailyVolume3Month"`
+	TrailingPE                  float64 `json:"trailingPE"`
+	TrailingAnnualDividendYield float64 `json:"trailingAnnualDividendYield"`
+	MarketState                 string  `json:"marketState"`
+	PreMarketPrice               float64 `json:"preMarketPrice"`
+	PreMarketChange               float64 `json:"preMarketChange"`
+	PreMarketChangePercent        float64 `json:"preMarketChangePercent"`
+	PostMarketPrice                float64 `json:"postMarketPrice"`
+	PostMarketChange               float64 `json:"postMarketChange"`
+	PostMarketChangePercent        float64 `json:"postMarketChangePercent"`
+}
+
+// yahooResultToStockInfo converts a single quote result into a StockInfo.
+func yahooResultToStockInfo(q yahooQuoteResult) *StockInfo {
+	companyName := q.LongName
+	if companyName == "" {
+		companyName = q.ShortName
+	}
+	if companyName == "" {
+		companyName = getCompanyNameFromTicker(q.Symbol)
+	}
+
+	info := &StockInfo{
+		Ticker:           q.Symbol,
+		CompanyName:      companyName,
+		Price:            q.RegularMarketPrice,
+		Change:           q.RegularMarketChange,
+		ChangePct:        fmt.Sprintf("%.2f%%", q.RegularMarketChangePercent),
+		Open:             q.RegularMarketOpen,
+		High:             q.RegularMarketDayHigh,
+		Low:              q.RegularMarketDayLow,
+		Volume:           formatVolume(q.RegularMarketVolume),
+		MarketCap:        formatMarketCap(q.MarketCap),
+		FiftyTwoWeekHigh: fmt.Sprintf("%.2f", q.FiftyTwoWeekHigh),
+		FiftyTwoWeekLow:  fmt.Sprintf("%.2f", q.FiftyTwoWeekLow),
+		AverageVolume:    formatVolume(q.AverageDailyVolume3Month),
+		PERatio:          fmt.Sprintf("%.2f", q.TrailingPE),
+		DividendYield:    fmt.Sprintf("%.2f%%", q.TrailingAnnualDividendYield*100),
+		MarketState:      q.MarketState,
+		DataAge:          0,
+	}
+
+	if q.PreMarketPrice > 0 {
+		info.PreMarketPrice = q.PreMarketPrice
+		info.PreMarketChange = q.PreMarketChange
+		info.PreMarketChangePct = fmt.Sprintf("%.2f%%", q.PreMarketChangePercent)
+	}
+	if q.PostMarketPrice > 0 {
+		info.PostMarketPrice = q.PostMarketPrice
+		info.PostMarketChange = q.PostMarketChange
+		info.PostMarketChangePct = fmt.Sprintf("%.2f%%", q.PostMarketChangePercent)
+	}
+
+	return info
+}
+
+// yahooQuoteBaseURL is a var rather than a const so tests can point it at an
+// httptest.Server instead of the real Yahoo endpoint.
+var yahooQuoteBaseURL = "https://query1.finance.yahoo.com/v7/finance/quote"
+
+func yahooQuoteURL(symbols, crumb string) string {
+	return fmt.Sprintf(
+		"%s?crumb=%s&symbols=%s&range=1d&interval=5m&includePrePost=true&corsDomain=finance.yahoo.com&.tsrc=finance",
+		yahooQuoteBaseURL, crumb, symbols)
+}
+
+// fetchYahooQuotes fetches symbols, authenticating with globalYahooAuth's
+// crumb and cookies as Yahoo now requires. A 401/403 is assumed to mean the
+// cached crumb/cookies went stale server-side, so it rotates them once and
+// retries before giving up.
+func fetchYahooQuotes(symbols string) (yahooQuoteResponse, error) {
+	var yahooResp yahooQuoteResponse
+
+	resp, err := doYahooQuoteRequest(symbols)
+	if err != nil {
+		return yahooResp, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		globalYahooAuth.invalidate()
+
+		resp, err = doYahooQuoteRequest(symbols)
+		if err != nil {
+			return yahooResp, err
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return yahooResp, fmt.Errorf("failed to read yahoo finance response: %v", err)
+	}
+
+	if err := json.Unmarshal(body, &yahooResp); err != nil {
+		return yahooResp, fmt.Errorf("failed to parse yahoo finance response: %v", err)
+	}
+	return yahooResp, nil
+}
+
+// doYahooQuoteRequest builds and issues a single quote request carrying the
+// current crumb and cookies. The caller is responsible for closing the
+// response body.
+func doYahooQuoteRequest(symbols string) (*http.Response, error) {
+	crumb, cookies, err := globalYahooAuth.credentials()
+	if err != nil {
+		return nil, fmt.Errorf("yahoo auth failed: %v", err)
+	}
+
+	req, err := http.NewRequest("GET", yahooQuoteURL(symbols, crumb), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", yahooUserAgent)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	resp, err := globalYahooAuth.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("yahoo finance request failed: %v", err)
+	}
+	return resp, nil
+}
+
+// Fetch retrieves a single ticker's quote.
+func (yahooQuoteProvider) Fetch(ticker string) (*StockInfo, error) {
+	yahooResp, err := fetchYahooQuotes(ticker)
+	if err != nil {
+		return nil, err
+	}
+	if len(yahooResp.QuoteResponse.Result) == 0 {
+		return nil, fmt.Errorf("no data found for ticker %s", ticker)
+	}
+	return yahooResultToStockInfo(yahooResp.QuoteResponse.Result[0]), nil
+}
+
+// FetchBatch resolves many tickers in a single request using Yahoo's
+// comma-joined symbols query, satisfying BatchQuoteProvider.
+func (yahooQuoteProvider) FetchBatch(tickers []string) (map[string]*StockInfo, error) {
+	if len(tickers) == 0 {
+		return nil, nil
+	}
+
+	yahooResp, err := fetchYahooQuotes(strings.Join(tickers, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*StockInfo, len(yahooResp.QuoteResponse.Result))
+	for _, q := range yahooResp.QuoteResponse.Result {
+		results[q.Symbol] = yahooResultToStockInfo(q)
+	}
+	return results, nil
+}
@@ -0,0 +1,181 @@
+package trading
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AlpacaExchange implements Exchange against Alpaca's paper-trading REST
+// API. It is configured from the same ALPACA_KEY_ID/ALPACA_SECRET_KEY
+// credentials as services.AlpacaProvider, plus an optional
+// ALPACA_TRADE_BASE_URL override for pointing at a different paper (or
+// live) endpoint.
+type AlpacaExchange struct {
+	keyID     string
+	secretKey string
+	baseURL   string
+
+	httpClient *http.Client
+}
+
+const defaultAlpacaTradeBaseURL = "https://paper-api.alpaca.markets"
+
+// NewAlpacaExchange builds an AlpacaExchange from the environment. It
+// returns an error if ALPACA_KEY_ID/ALPACA_SECRET_KEY aren't configured.
+func NewAlpacaExchange() (*AlpacaExchange, error) {
+	keyID := os.Getenv("ALPACA_KEY_ID")
+	secretKey := os.Getenv("ALPACA_SECRET_KEY")
+	if keyID == "" || secretKey == "" {
+		return nil, fmt.Errorf("ALPACA_KEY_ID and ALPACA_SECRET_KEY must be set")
+	}
+
+	baseURL := os.Getenv("ALPACA_TRADE_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultAlpacaTradeBaseURL
+	}
+
+	return &AlpacaExchange{
+		keyID:      keyID,
+		secretKey:  secretKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (a *AlpacaExchange) authHeaders(req *http.Request) {
+	req.Header.Set("APCA-API-KEY-ID", a.keyID)
+	req.Header.Set("APCA-API-SECRET-KEY", a.secretKey)
+	req.Header.Set("Content-Type", "application/json")
+}
+
+// alpacaOrderResponse mirrors the fields of Alpaca's order JSON that
+// SubmitOrder/CancelOrder care about.
+type alpacaOrderResponse struct {
+	ID             string `json:"id"`
+	Symbol         string `json:"symbol"`
+	Side           string `json:"side"`
+	Type           string `json:"type"`
+	Qty            string `json:"qty"`
+	FilledQty      string `json:"filled_qty"`
+	FilledAvgPrice string `json:"filled_avg_price"`
+	Status         string `json:"status"`
+}
+
+func (r alpacaOrderResponse) toOrder() Order {
+	qty, _ := strconv.ParseFloat(r.Qty, 64)
+	filledQty, _ := strconv.ParseFloat(r.FilledQty, 64)
+	filledAvgPrice, _ := strconv.ParseFloat(r.FilledAvgPrice, 64)
+
+	return Order{
+		ID:             r.ID,
+		Symbol:         r.Symbol,
+		Side:           Side(r.Side),
+		Type:           OrderType(r.Type),
+		Qty:            qty,
+		FilledQty:      filledQty,
+		FilledAvgPrice: filledAvgPrice,
+		Status:         alpacaStatusToOrderStatus(r.Status),
+	}
+}
+
+// alpacaStatusToOrderStatus maps Alpaca's order status strings onto
+// OrderStatus. Alpaca reports several granular "working" states (accepted,
+// pending_new, ...) that all map to OrderNew here since ActiveOrderBook only
+// distinguishes new/partially-filled/terminal.
+func alpacaStatusToOrderStatus(status string) OrderStatus {
+	switch status {
+	case "filled":
+		return OrderFilled
+	case "partially_filled":
+		return OrderPartiallyFilled
+	case "canceled", "expired":
+		return OrderCanceled
+	case "rejected":
+		return OrderRejected
+	default:
+		return OrderNew
+	}
+}
+
+// SubmitOrder places req on Alpaca's paper-trading account via POST
+// /v2/orders.
+func (a *AlpacaExchange) SubmitOrder(ctx context.Context, req SubmitOrderRequest) (Order, error) {
+	payload := map[string]interface{}{
+		"symbol":        req.Symbol,
+		"side":          string(req.Side),
+		"type":          string(req.Type),
+		"qty":           fmt.Sprintf("%.6f", req.Qty),
+		"time_in_force": string(req.TimeInForce),
+	}
+	if req.Type == OrderTypeLimit {
+		payload["limit_price"] = fmt.Sprintf("%.2f", req.LimitPrice)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Order{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/v2/orders", bytes.NewReader(body))
+	if err != nil {
+		return Order{}, err
+	}
+	a.authHeaders(httpReq)
+
+	resp, err := a.httpClient.Do(httpReq)
+	if err != nil {
+		return Order{}, fmt.Errorf("alpaca submit order request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Order{}, fmt.Errorf("failed to read alpaca order response: %v", err)
+	}
+
+	if resp.StatusCode == http.StatusForbidden && strings.Contains(strings.ToLower(string(respBody)), "insufficient") {
+		return Order{}, fmt.Errorf("alpaca rejected order for %s: %w", req.Symbol, ErrInsufficientBalance)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Order{}, fmt.Errorf("alpaca submit order returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var orderResp alpacaOrderResponse
+	if err := json.Unmarshal(respBody, &orderResp); err != nil {
+		return Order{}, fmt.Errorf("failed to parse alpaca order response: %v", err)
+	}
+
+	return orderResp.toOrder(), nil
+}
+
+// CancelOrder cancels orderID via DELETE /v2/orders/{id}. A 404 is treated
+// as success since the order is already gone (filled or previously
+// canceled).
+func (a *AlpacaExchange) CancelOrder(ctx context.Context, orderID string) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, a.baseURL+"/v2/orders/"+orderID, nil)
+	if err != nil {
+		return err
+	}
+	a.authHeaders(httpReq)
+
+	resp, err := a.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("alpaca cancel order request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("alpaca cancel order returned status %d: %s", resp.StatusCode, string(body))
+}
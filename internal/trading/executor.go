@@ -0,0 +1,187 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// ErrInsufficientBalance is returned (wrapped) by an Exchange's SubmitOrder
+// when the account doesn't have enough buying power/shares for the
+// requested quantity, signaling OrderExecutor.SubmitOrder to retry at a
+// reduced size.
+var ErrInsufficientBalance = errors.New("trading: insufficient balance")
+
+// Exchange is the broker API an OrderExecutor submits and cancels orders
+// against. AlpacaExchange implements it against Alpaca's paper-trading REST
+// API; tests use a fake.
+type Exchange interface {
+	SubmitOrder(ctx context.Context, req SubmitOrderRequest) (Order, error)
+	CancelOrder(ctx context.Context, orderID string) error
+}
+
+const (
+	// defaultMaxCancelRetries caps GracefulCancel's retry-with-backoff loop
+	// per order.
+	defaultMaxCancelRetries = 5
+
+	// qtyReductionFactor shrinks the requested quantity by 0.5% per retry
+	// after an insufficient-balance rejection, mirroring bbgo's executor.
+	qtyReductionFactor = 0.995
+
+	// maxQtyReductionAttempts bounds how many times SubmitOrder will shrink
+	// and resubmit before giving up.
+	maxQtyReductionAttempts = 5
+
+	defaultCancelBaseBackoff = 500 * time.Millisecond
+	defaultCancelMaxBackoff  = 10 * time.Second
+)
+
+// OrderEvent is emitted on OrderExecutor.Events whenever an order is
+// submitted, updated, or reaches a terminal status, for callers to relay
+// out-of-band (e.g. over the WebSocket order channel).
+type OrderEvent struct {
+	Order Order
+	Type  string // "submitted", "canceled", "rejected"
+}
+
+// OrderExecutor submits and cancels orders against an Exchange, tracking
+// every open order in an ActiveOrderBook and emitting an OrderEvent for
+// each submission and cancellation. It is modeled on bbgo's
+// BaseOrderExecutor.
+type OrderExecutor struct {
+	exchange Exchange
+	book     *ActiveOrderBook
+
+	maxCancelRetries  int
+	cancelBaseBackoff time.Duration
+	cancelMaxBackoff  time.Duration
+	events            chan OrderEvent
+}
+
+// NewOrderExecutor builds an OrderExecutor backed by exchange, with its own
+// ActiveOrderBook and a buffered event channel.
+func NewOrderExecutor(exchange Exchange) *OrderExecutor {
+	return &OrderExecutor{
+		exchange:          exchange,
+		book:              NewActiveOrderBook(),
+		maxCancelRetries:  defaultMaxCancelRetries,
+		cancelBaseBackoff: defaultCancelBaseBackoff,
+		cancelMaxBackoff:  defaultCancelMaxBackoff,
+		events:            make(chan OrderEvent, 64),
+	}
+}
+
+// Events returns the channel OrderExecutor publishes order lifecycle
+// updates to. Callers should drain it continuously; a full buffer causes
+// SubmitOrder/GracefulCancel to drop the event rather than block.
+func (e *OrderExecutor) Events() <-chan OrderEvent {
+	return e.events
+}
+
+// Book returns the executor's ActiveOrderBook.
+func (e *OrderExecutor) Book() *ActiveOrderBook {
+	return e.book
+}
+
+func (e *OrderExecutor) publish(order Order, eventType string) {
+	select {
+	case e.events <- OrderEvent{Order: order, Type: eventType}:
+	default:
+		log.Printf("trading: dropping order event %s for %s (buffer full)", eventType, order.ID)
+	}
+}
+
+// SubmitOrder places req against the exchange, tracking the resulting order
+// in the book and publishing a "submitted" event. If the exchange rejects
+// the order for insufficient balance, the quantity is reduced by 0.5% and
+// resubmitted, up to maxQtyReductionAttempts times, the same recovery bbgo
+// applies before giving up on a position.
+func (e *OrderExecutor) SubmitOrder(ctx context.Context, req SubmitOrderRequest) (Order, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= maxQtyReductionAttempts; attempt++ {
+		order, err := e.exchange.SubmitOrder(ctx, req)
+		if err == nil {
+			order.SubmittedAt = time.Now()
+			order.UpdatedAt = order.SubmittedAt
+			e.book.Put(order)
+			e.publish(order, "submitted")
+			return order, nil
+		}
+
+		lastErr = err
+		if !errors.Is(err, ErrInsufficientBalance) || attempt == maxQtyReductionAttempts {
+			break
+		}
+
+		req.Qty *= qtyReductionFactor
+		log.Printf("trading: insufficient balance for %s, reducing qty to %.6f and retrying", req.Symbol, req.Qty)
+	}
+
+	return Order{}, fmt.Errorf("trading: submit order for %s failed after retries: %w", req.Symbol, lastErr)
+}
+
+// GracefulCancel cancels each of orders, retrying with exponential backoff
+// (capped at cancelMaxBackoff) up to maxCancelRetries times per order
+// before giving up on it. It returns a combined error for every order that
+// couldn't be canceled, or nil if all succeeded.
+func (e *OrderExecutor) GracefulCancel(ctx context.Context, orders ...Order) error {
+	var errs []error
+
+	for _, order := range orders {
+		if err := e.cancelWithRetry(ctx, order); err != nil {
+			errs = append(errs, fmt.Errorf("order %s: %w", order.ID, err))
+			continue
+		}
+
+		order.Status = OrderCanceled
+		order.UpdatedAt = time.Now()
+		e.book.Put(order)
+		e.publish(order, "canceled")
+	}
+
+	return errors.Join(errs...)
+}
+
+// cancelWithRetry calls Exchange.CancelOrder, retrying with exponential
+// backoff plus jitter up to e.maxCancelRetries times.
+func (e *OrderExecutor) cancelWithRetry(ctx context.Context, order Order) error {
+	backoff := e.cancelBaseBackoff
+	var lastErr error
+
+	for attempt := 0; attempt <= e.maxCancelRetries; attempt++ {
+		if err := e.exchange.CancelOrder(ctx, order.ID); err == nil {
+			return nil
+		} else {
+			lastErr = err
+		}
+
+		if attempt == e.maxCancelRetries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff + time.Duration(rand.Intn(100))*time.Millisecond):
+		}
+
+		backoff *= 2
+		if backoff > e.cancelMaxBackoff {
+			backoff = e.cancelMaxBackoff
+		}
+	}
+
+	return fmt.Errorf("cancel failed after %d attempts: %w", e.maxCancelRetries+1, lastErr)
+}
+
+// CancelAll is the kill-switch GracefulCancel is wrapped in for shutdown:
+// it cancels every order currently tracked by the book, across all
+// symbols.
+func (e *OrderExecutor) CancelAll(ctx context.Context) error {
+	return e.GracefulCancel(ctx, e.book.All()...)
+}
@@ -0,0 +1,144 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeExchange is a test double implementing Exchange with scriptable
+// per-call behavior.
+type fakeExchange struct {
+	submitCalls int
+	submitFunc  func(req SubmitOrderRequest, call int) (Order, error)
+
+	cancelCalls int
+	cancelFunc  func(orderID string, call int) error
+}
+
+func (f *fakeExchange) SubmitOrder(ctx context.Context, req SubmitOrderRequest) (Order, error) {
+	f.submitCalls++
+	return f.submitFunc(req, f.submitCalls)
+}
+
+func (f *fakeExchange) CancelOrder(ctx context.Context, orderID string) error {
+	f.cancelCalls++
+	return f.cancelFunc(orderID, f.cancelCalls)
+}
+
+func TestSubmitOrderReducesQtyOnInsufficientBalance(t *testing.T) {
+	var gotQtys []float64
+
+	exchange := &fakeExchange{
+		submitFunc: func(req SubmitOrderRequest, call int) (Order, error) {
+			gotQtys = append(gotQtys, req.Qty)
+			if call < 3 {
+				return Order{}, fmt.Errorf("rejected: %w", ErrInsufficientBalance)
+			}
+			return Order{ID: "abc", Symbol: req.Symbol, Qty: req.Qty, Status: OrderNew}, nil
+		},
+	}
+
+	executor := NewOrderExecutor(exchange)
+	order, err := executor.SubmitOrder(context.Background(), SubmitOrderRequest{Symbol: "AAPL", Qty: 10})
+	if err != nil {
+		t.Fatalf("expected success after retries, got error: %v", err)
+	}
+	if order.ID != "abc" {
+		t.Errorf("expected order ID 'abc', got %q", order.ID)
+	}
+	if len(gotQtys) != 3 {
+		t.Fatalf("expected 3 submit attempts, got %d", len(gotQtys))
+	}
+	if gotQtys[1] >= gotQtys[0] || gotQtys[2] >= gotQtys[1] {
+		t.Errorf("expected qty to shrink each retry, got %v", gotQtys)
+	}
+
+	open := executor.Book().OrdersFor("AAPL")
+	if len(open) != 1 {
+		t.Fatalf("expected order to be tracked in the book, got %d open orders", len(open))
+	}
+}
+
+func TestSubmitOrderGivesUpAfterMaxReductions(t *testing.T) {
+	exchange := &fakeExchange{
+		submitFunc: func(req SubmitOrderRequest, call int) (Order, error) {
+			return Order{}, fmt.Errorf("rejected: %w", ErrInsufficientBalance)
+		},
+	}
+
+	executor := NewOrderExecutor(exchange)
+	_, err := executor.SubmitOrder(context.Background(), SubmitOrderRequest{Symbol: "AAPL", Qty: 10})
+	if err == nil {
+		t.Fatal("expected an error after exhausting qty-reduction attempts")
+	}
+	if exchange.submitCalls != maxQtyReductionAttempts+1 {
+		t.Errorf("expected %d submit attempts, got %d", maxQtyReductionAttempts+1, exchange.submitCalls)
+	}
+}
+
+func TestSubmitOrderStopsRetryingOnOtherErrors(t *testing.T) {
+	exchange := &fakeExchange{
+		submitFunc: func(req SubmitOrderRequest, call int) (Order, error) {
+			return Order{}, errors.New("symbol not tradable")
+		},
+	}
+
+	executor := NewOrderExecutor(exchange)
+	_, err := executor.SubmitOrder(context.Background(), SubmitOrderRequest{Symbol: "AAPL", Qty: 10})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if exchange.submitCalls != 1 {
+		t.Errorf("expected no retries for a non-balance error, got %d submit attempts", exchange.submitCalls)
+	}
+}
+
+func TestGracefulCancelRetriesThenSucceeds(t *testing.T) {
+	exchange := &fakeExchange{
+		cancelFunc: func(orderID string, call int) error {
+			if call < 2 {
+				return errors.New("temporarily unavailable")
+			}
+			return nil
+		},
+	}
+
+	executor := NewOrderExecutor(exchange)
+	executor.cancelBaseBackoff = time.Millisecond
+	executor.cancelMaxBackoff = time.Millisecond
+	order := Order{ID: "xyz", Symbol: "AAPL", Status: OrderNew}
+	executor.Book().Put(order)
+
+	if err := executor.GracefulCancel(context.Background(), order); err != nil {
+		t.Fatalf("expected cancel to eventually succeed, got: %v", err)
+	}
+	if exchange.cancelCalls != 2 {
+		t.Errorf("expected 2 cancel attempts, got %d", exchange.cancelCalls)
+	}
+	if open := executor.Book().OrdersFor("AAPL"); len(open) != 0 {
+		t.Errorf("expected order to be removed from the book after cancel, got %d open orders", len(open))
+	}
+}
+
+func TestGracefulCancelGivesUpAfterMaxRetries(t *testing.T) {
+	exchange := &fakeExchange{
+		cancelFunc: func(orderID string, call int) error {
+			return errors.New("exchange unreachable")
+		},
+	}
+
+	executor := NewOrderExecutor(exchange)
+	executor.cancelBaseBackoff = time.Millisecond
+	executor.cancelMaxBackoff = time.Millisecond
+	order := Order{ID: "xyz", Symbol: "AAPL", Status: OrderNew}
+
+	if err := executor.GracefulCancel(context.Background(), order); err == nil {
+		t.Fatal("expected an error after exhausting cancel retries")
+	}
+	if exchange.cancelCalls != defaultMaxCancelRetries+1 {
+		t.Errorf("expected %d cancel attempts, got %d", defaultMaxCancelRetries+1, exchange.cancelCalls)
+	}
+}
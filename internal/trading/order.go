@@ -0,0 +1,156 @@
+// Package trading turns Investify's read-only analysis into an order
+// execution layer: OrderExecutor submits and cancels orders against an
+// Exchange (implemented for Alpaca's paper-trading API) and tracks them in
+// an ActiveOrderBook, emitting events callers can push out over the
+// WebSocket order channel.
+package trading
+
+import (
+	"sync"
+	"time"
+)
+
+// OrderStatus mirrors the lifecycle Alpaca (and most broker APIs) report an
+// order transitioning through.
+type OrderStatus string
+
+const (
+	OrderNew             OrderStatus = "new"
+	OrderPartiallyFilled OrderStatus = "partiallyFilled"
+	OrderFilled          OrderStatus = "filled"
+	OrderCanceled        OrderStatus = "canceled"
+	OrderRejected        OrderStatus = "rejected"
+)
+
+// terminal reports whether status is one an order cannot leave.
+func (s OrderStatus) terminal() bool {
+	return s == OrderFilled || s == OrderCanceled || s == OrderRejected
+}
+
+// Side is the order's buy/sell direction.
+type Side string
+
+const (
+	SideBuy  Side = "buy"
+	SideSell Side = "sell"
+)
+
+// OrderType is the order's pricing type.
+type OrderType string
+
+const (
+	OrderTypeMarket OrderType = "market"
+	OrderTypeLimit  OrderType = "limit"
+)
+
+// TimeInForce controls how long an order remains working.
+type TimeInForce string
+
+const (
+	TimeInForceDay TimeInForce = "day"
+	TimeInForceGTC TimeInForce = "gtc"
+)
+
+// SubmitOrderRequest describes an order to place. LimitPrice is ignored for
+// OrderTypeMarket.
+type SubmitOrderRequest struct {
+	Symbol      string
+	Side        Side
+	Type        OrderType
+	Qty         float64
+	LimitPrice  float64
+	TimeInForce TimeInForce
+}
+
+// Order is the executor's view of an order, kept in sync with the exchange
+// via SubmitOrder's response and any later status updates.
+type Order struct {
+	ID             string
+	Symbol         string
+	Side           Side
+	Type           OrderType
+	Qty            float64
+	FilledQty      float64
+	FilledAvgPrice float64
+	Status         OrderStatus
+	SubmittedAt    time.Time
+	UpdatedAt      time.Time
+}
+
+// ActiveOrderBook tracks open orders keyed by symbol, then order ID, so a
+// caller can look up or cancel every working order for a ticker without
+// scanning the whole book. Orders are removed once they reach a terminal
+// status.
+type ActiveOrderBook struct {
+	mu       sync.RWMutex
+	bySymbol map[string]map[string]*Order
+}
+
+// NewActiveOrderBook creates an empty order book.
+func NewActiveOrderBook() *ActiveOrderBook {
+	return &ActiveOrderBook{bySymbol: make(map[string]map[string]*Order)}
+}
+
+// Put inserts or updates order, removing it from the book once its status
+// becomes terminal.
+func (b *ActiveOrderBook) Put(order Order) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if order.Status.terminal() {
+		if orders := b.bySymbol[order.Symbol]; orders != nil {
+			delete(orders, order.ID)
+			if len(orders) == 0 {
+				delete(b.bySymbol, order.Symbol)
+			}
+		}
+		return
+	}
+
+	if b.bySymbol[order.Symbol] == nil {
+		b.bySymbol[order.Symbol] = make(map[string]*Order)
+	}
+	o := order
+	b.bySymbol[order.Symbol][order.ID] = &o
+}
+
+// OrdersFor returns the currently open orders for symbol.
+func (b *ActiveOrderBook) OrdersFor(symbol string) []Order {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	orders := make([]Order, 0, len(b.bySymbol[symbol]))
+	for _, o := range b.bySymbol[symbol] {
+		orders = append(orders, *o)
+	}
+	return orders
+}
+
+// FindByID returns the open order with the given ID, searching across every
+// symbol, for callers (like a cancel-by-ID API) that only have the order ID
+// on hand.
+func (b *ActiveOrderBook) FindByID(orderID string) (Order, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, orders := range b.bySymbol {
+		if o, ok := orders[orderID]; ok {
+			return *o, true
+		}
+	}
+	return Order{}, false
+}
+
+// All returns every currently open order across all symbols.
+func (b *ActiveOrderBook) All() []Order {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	all := make([]Order, 0)
+	for _, orders := range b.bySymbol {
+		for _, o := range orders {
+			all = append(all, *o)
+		}
+	}
+	return all
+}
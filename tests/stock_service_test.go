@@ -77,15 +77,15 @@ func TestAIService(t *testing.T) {
 	
 	// Test the rule-based recommendation
 	recommendation := services.GetRuleBasedRecommendation(stockInfo)
-	if recommendation == "" {
+	if recommendation.Text == "" {
 		t.Errorf("Expected non-empty recommendation")
 	}
-	
+
 	// Check that recommendation includes BUY, SELL, or HOLD
-	if !strings.Contains(recommendation, "BUY") && 
-	   !strings.Contains(recommendation, "SELL") && 
-	   !strings.Contains(recommendation, "HOLD") {
-		t.Errorf("Recommendation should contain BUY, SELL or HOLD, got: %s", recommendation)
+	if !strings.Contains(recommendation.Text, "BUY") &&
+	   !strings.Contains(recommendation.Text, "SELL") &&
+	   !strings.Contains(recommendation.Text, "HOLD") {
+		t.Errorf("Recommendation should contain BUY, SELL or HOLD, got: %s", recommendation.Text)
 	}
 }
 